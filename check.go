@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server"
+	"io"
+	"log"
+	"os"
+)
+
+// checkResult is the result of checking a single lecture
+type checkResult struct {
+	Source   string           `json:"source"`
+	Lecture  string           `json:"lecture,omitempty"`
+	Tasks    int              `json:"tasks"`
+	Error    string           `json:"error,omitempty"`
+	Problems []server.Problem `json:"problems,omitempty"`
+}
+
+func (c checkResult) ok() bool {
+	return c.Error == "" && len(c.Problems) == 0
+}
+
+// check reads all given lectures, initializes them, which also runs all
+// validator tests, and checks the markdown texts.
+// It returns the exit code.
+func check(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	asJson := flags.Bool("json", false, "writes the report as json")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: quiz check [-json] <folder-or-zip>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	// the lecture init logs to stderr, which is not needed here
+	log.SetOutput(io.Discard)
+
+	var results []checkResult
+	exitCode := 0
+	for _, path := range flags.Args() {
+		result := checkLecture(path)
+		if !result.ok() {
+			exitCode = 1
+		}
+		results = append(results, result)
+	}
+
+	if *asJson {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(results)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	} else {
+		for _, r := range results {
+			writeCheckResult(os.Stdout, r)
+		}
+	}
+	return exitCode
+}
+
+// checkLecture checks a single lecture. A panic while reading the lecture
+// is reported as an error of this lecture, so the other lectures are
+// still checked.
+func checkLecture(path string) (result checkResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = checkResult{Source: path, Error: fmt.Sprint(r)}
+		}
+	}()
+
+	lecture, err := data.ReadLecture(path)
+	if err != nil {
+		return checkResult{Source: path, Error: err.Error()}
+	}
+	return checkResult{
+		Source:   path,
+		Lecture:  lecture.Title,
+		Tasks:    lecture.TaskCount(),
+		Problems: server.CheckMarkdown(lecture),
+	}
+}
+
+func writeCheckResult(w io.Writer, r checkResult) {
+	if r.ok() {
+		fmt.Fprintf(w, "%s: ok, lecture '%s' with %d tasks\n", r.Source, r.Lecture, r.Tasks)
+		return
+	}
+	if r.Error != "" {
+		fmt.Fprintf(w, "%s: error: %s\n", r.Source, r.Error)
+		return
+	}
+	fmt.Fprintf(w, "%s: %d problem(s) in lecture '%s'\n", r.Source, len(r.Problems), r.Lecture)
+	for _, p := range r.Problems {
+		fmt.Fprintf(w, "  %s: %s\n", p.Where, p.Message)
+	}
+}
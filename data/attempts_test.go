@@ -0,0 +1,60 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAttemptLimit(t *testing.T) {
+	lecture, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter maxAttempts="3">
+        <Title>Gleichstromkreise</Title>
+        <Chapter>
+            <Title>Spannung</Title>
+            <Task>
+                <Question>Frage</Question>
+                <Input id="a" type="text">
+                    <Label>a:</Label>
+                    <Validator>
+                        <Expression>cmpValues(1,answer.a,1)</Expression>
+                        <Explanation>Es gilt $a=1$.</Explanation>
+                    </Validator>
+                </Input>
+            </Task>
+            <Task maxAttempts="1">
+                <Question>Frage</Question>
+                <Input id="a" type="checkbox">
+                    <Label>a:</Label>
+                    <Validator>
+                        <Expression>answer.a</Expression>
+                    </Validator>
+                </Input>
+            </Task>
+        </Chapter>
+    </Chapter>
+    <Chapter>
+        <Title>Strom</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+    </Chapter>
+</Lecture>`)
+	assert.NoError(t, err)
+
+	sub := lecture.Chapter[0].Chapter[0]
+	assert.Equal(t, 3, sub.Task[0].AttemptLimit())
+	assert.Equal(t, 1, sub.Task[1].AttemptLimit())
+	assert.Equal(t, 0, lecture.Chapter[1].Task[0].AttemptLimit())
+
+	assert.Equal(t, map[InputId]string{"a": "Lösung:\n\nEs gilt $a=1$."}, sub.Task[0].Explanations(nil))
+	assert.Equal(t, 0, len(sub.Task[1].Explanations(nil)))
+}
@@ -0,0 +1,398 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hneemann/parser2"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"log"
+	"slices"
+	"strings"
+)
+
+// maxBoolVars is the maximum number of variables of a boolean function
+const maxBoolVars = 12
+
+// emptyCell marks a cell of a truth table not filled in by the user
+const emptyCell = '_'
+
+// boolParser parses the boolean expressions given by the user.
+// The operator added first has the lowest priority.
+var boolParser = funcGen.New[bool]().
+	AddSimpleOp("|", true, func(a, b bool) (bool, error) { return a || b, nil }).
+	AddSimpleOp("⊕", true, func(a, b bool) (bool, error) { return a != b, nil }).
+	AddSimpleOp("⊼", true, func(a, b bool) (bool, error) { return !(a && b), nil }).
+	AddSimpleOp("&", true, func(a, b bool) (bool, error) { return a && b, nil }).
+	AddUnary("!", func(a bool) (bool, error) { return !a, nil }).
+	SetNumberParser(
+		parser2.NumberParserFunc[bool](
+			func(n string) (bool, error) {
+				switch n {
+				case "0":
+					return false, nil
+				case "1":
+					return true, nil
+				}
+				return false, fmt.Errorf("'%s' is not a boolean constant", n)
+			},
+		),
+	).
+	Modify(func(f *funcGen.FunctionGenerator[bool]) {
+		f.GetParser().TextOperator(map[string]string{"or": "|", "and": "&", "xor": "⊕", "nand": "⊼"})
+	})
+
+// isTruthTable returns true if the string is the output column of a
+// truth table with the given number of variables
+func isTruthTable(s string, vars int) bool {
+	if vars == 0 || len(s) != 1<<vars {
+		return false
+	}
+	for _, c := range s {
+		if c != '0' && c != '1' && c != emptyCell {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBool parses a boolean expression given by the user
+func parseBool(expr string, vars []string) (funcGen.Func[bool], parser2.AST, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil, GuiError{message: "Die Eingabe ist leer!"}
+	}
+	fu, err := boolParser.Generate(expr, vars...)
+	if err != nil {
+		log.Printf("error parsing boolean expression '%s': %v", expr, err)
+		return nil, nil, GuiError{message: fmt.Sprintf("Der Ausdruck '%s' enthält Fehler und kann nicht analysiert werden!", expr), cause: err}
+	}
+	ast, err := boolParser.GetParser().Parse(expr)
+	if err != nil {
+		return nil, nil, GuiError{message: fmt.Sprintf("Der Ausdruck '%s' enthält Fehler und kann nicht analysiert werden!", expr), cause: err}
+	}
+	return fu, ast, nil
+}
+
+// truthTable returns the output column of the truth table of the given expression.
+// In the first row all variables are zero, in the last row all variables are one.
+// The first variable is the most significant one.
+// If the expression already is the output column of a truth table, it is returned as it is.
+func truthTable(expr string, vars []string) (string, error) {
+	if len(vars) > maxBoolVars {
+		return "", fmt.Errorf("too many variables, at most %d are allowed", maxBoolVars)
+	}
+	expr = strings.TrimSpace(expr)
+	if isTruthTable(expr, len(vars)) {
+		return expr, nil
+	}
+
+	fu, _, err := parseBool(expr, vars)
+	if err != nil {
+		return "", err
+	}
+
+	rows := 1 << len(vars)
+	var sb strings.Builder
+	args := make([]bool, len(vars))
+	for r := 0; r < rows; r++ {
+		for i := range args {
+			args[i] = r&(1<<(len(vars)-1-i)) != 0
+		}
+		v, err := fu.Eval(args...)
+		if err != nil {
+			return "", GuiError{message: fmt.Sprintf("Fehler bei der Berechnung von '%s'", expr), cause: err}
+		}
+		if v {
+			sb.WriteRune('1')
+		} else {
+			sb.WriteRune('0')
+		}
+	}
+	return sb.String(), nil
+}
+
+// cmpBool compares two boolean functions by their truth tables.
+// Both functions can be given as an expression or as the output column of a
+// truth table as created by a truth table input.
+func cmpBool(expected, answer string, vars []string) (value.Value, error) {
+	exp, err := truthTable(expected, vars)
+	if err != nil {
+		return nil, fmt.Errorf("error in expected function: %w", err)
+	}
+	if strings.ContainsRune(exp, emptyCell) {
+		return nil, fmt.Errorf("the expected truth table '%s' is incomplete", exp)
+	}
+	is, err := truthTable(answer, vars)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsRune(is, emptyCell) {
+		return value.String("Die Wahrheitstabelle ist nicht vollständig ausgefüllt!"), nil
+	}
+	return value.Bool(exp == is), nil
+}
+
+// normalForm describes the structure of a boolean expression
+type normalForm struct {
+	dnf      bool
+	knf      bool
+	literals int
+}
+
+// literalsVisitor counts the variables in an expression
+type literalsVisitor struct {
+	n int
+}
+
+func (l *literalsVisitor) Visit(ast parser2.AST) bool {
+	if _, ok := ast.(*parser2.Ident); ok {
+		l.n++
+	}
+	return true
+}
+
+// isLiteral returns true if the ast is a variable or a negated variable
+func isLiteral(ast parser2.AST) bool {
+	switch a := ast.(type) {
+	case *parser2.Ident:
+		return true
+	case *parser2.Unary:
+		if a.Operator == "!" {
+			_, ok := a.Value.(*parser2.Ident)
+			return ok
+		}
+	}
+	return false
+}
+
+// isChain returns true if the ast is a chain of literals combined by the given operator
+func isChain(ast parser2.AST, op string) bool {
+	if isLiteral(ast) {
+		return true
+	}
+	if o, ok := ast.(*parser2.Operate); ok && o.Operator == op {
+		return isChain(o.A, op) && isChain(o.B, op)
+	}
+	return false
+}
+
+// isNormalForm returns true if the ast is a chain of terms combined by the outer operator.
+// Each term is a chain of literals combined by the inner operator.
+func isNormalForm(ast parser2.AST, outer, inner string) bool {
+	if isChain(ast, inner) {
+		return true
+	}
+	if o, ok := ast.(*parser2.Operate); ok && o.Operator == outer {
+		return isNormalForm(o.A, outer, inner) && isNormalForm(o.B, outer, inner)
+	}
+	return false
+}
+
+// getNormalForm returns the structure of the given expression
+func getNormalForm(ast parser2.AST) normalForm {
+	v := literalsVisitor{}
+	ast.Traverse(&v)
+	if _, ok := ast.(*parser2.Const[bool]); ok {
+		return normalForm{dnf: true, knf: true}
+	}
+	return normalForm{
+		dnf:      isNormalForm(ast, "|", "&"),
+		knf:      isNormalForm(ast, "&", "|"),
+		literals: v.n,
+	}
+}
+
+// boolCplx checks if the answer is a minimal disjunctive or conjunctive
+// normal form of the expected expression. The expected expression needs to
+// be a minimal normal form. If it is a DNF, the answer has to be a DNF,
+// if it is a KNF, the answer has to be a KNF.
+func boolCplx(expected, answer string, vars []string) (value.Value, error) {
+	_, expAst, err := parseBool(expected, vars)
+	if err != nil {
+		return nil, fmt.Errorf("error in expected function: %w", err)
+	}
+	expForm := getNormalForm(expAst)
+	if !expForm.dnf && !expForm.knf {
+		return nil, fmt.Errorf("the expected function '%s' is neither a DNF nor a KNF", expected)
+	}
+
+	_, isAst, err := parseBool(answer, vars)
+	if err != nil {
+		return nil, err
+	}
+	eq, err := cmpBool(expected, answer, vars)
+	if err != nil {
+		return nil, err
+	}
+	if eq != value.Bool(true) {
+		return value.String("Der Ausdruck ist nicht korrekt!"), nil
+	}
+
+	isForm := getNormalForm(isAst)
+	if !(expForm.dnf && isForm.dnf) && !(expForm.knf && isForm.knf) {
+		switch {
+		case !expForm.knf:
+			return value.String("Der Ausdruck ist zwar korrekt, aber keine disjunktive Normalform!"), nil
+		case !expForm.dnf:
+			return value.String("Der Ausdruck ist zwar korrekt, aber keine konjunktive Normalform!"), nil
+		default:
+			return value.String("Der Ausdruck ist zwar korrekt, aber keine Normalform!"), nil
+		}
+	}
+	if isForm.literals > expForm.literals {
+		return value.String("Der Ausdruck ist zwar korrekt, aber nicht minimal!"), nil
+	}
+	return value.Bool(true), nil
+}
+
+// toStringList converts a list of strings
+func toStringList(stack funcGen.Stack[value.Value], v value.Value) ([]string, error) {
+	list, ok := v.(*value.List)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %v", v)
+	}
+	values, err := list.ToSlice(stack)
+	if err != nil {
+		return nil, err
+	}
+	var strs []string
+	for _, v := range values {
+		if str, ok := v.(value.String); ok {
+			strs = append(strs, string(str))
+		} else {
+			return nil, fmt.Errorf("expected string, got %v", v)
+		}
+	}
+	return strs, nil
+}
+
+// boolArgs returns the arguments of the boolean compare functions
+func boolArgs(stack funcGen.Stack[value.Value]) (string, string, []string, error) {
+	expected, err := stack.Get(0).ToString(stack)
+	if err != nil {
+		return "", "", nil, err
+	}
+	answer, err := stack.Get(1).ToString(stack)
+	if err != nil {
+		return "", "", nil, err
+	}
+	vars, err := toStringList(stack, stack.Get(2))
+	if err != nil {
+		return "", "", nil, err
+	}
+	return expected, answer, vars, nil
+}
+
+func addBoolFunctions(f *funcGen.FunctionGenerator[value.Value]) {
+	f.AddStaticFunction("cmpBool", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, answer, vars, err := boolArgs(stack)
+			if err != nil {
+				return nil, err
+			}
+			return cmpBool(expected, answer, vars)
+		},
+		Args:   3,
+		IsPure: true,
+	}.SetDescription("expected", "is", "argList",
+		"compares two boolean functions using their truth tables. The functions can be given as an expression "+
+			"or as the output column of a truth table, e.g. \"0110\""))
+	f.AddStaticFunction("boolCplx", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, answer, vars, err := boolArgs(stack)
+			if err != nil {
+				return nil, err
+			}
+			return boolCplx(expected, answer, vars)
+		},
+		Args:   3,
+		IsPure: true,
+	}.SetDescription("expected", "is", "argList",
+		"compares two boolean functions and checks if the given function is a normal form "+
+			"which contains at most as many literals as the expected minimal DNF or KNF"))
+}
+
+// maxTableVars is the maximum number of variables of a truth table input
+const maxTableVars = 5
+
+// TableRow is a row of a truth table input
+type TableRow struct {
+	Index  int
+	Values []int
+}
+
+// IsTruthTable returns true if the input is a truth table
+func (i *Input) IsTruthTable() bool {
+	return i.Type == TruthTable
+}
+
+// TableVars returns the variables of a truth table input
+func (i *Input) TableVars() []string {
+	return i.tableVars
+}
+
+// TableRows returns the rows of a truth table input.
+// The order of the rows matches the order used by cmpBool.
+func (i *Input) TableRows() []TableRow {
+	n := len(i.tableVars)
+	rows := make([]TableRow, 1<<n)
+	for r := range rows {
+		values := make([]int, n)
+		for v := range values {
+			values[v] = (r >> (n - 1 - v)) & 1
+		}
+		rows[r] = TableRow{Index: r, Values: values}
+	}
+	return rows
+}
+
+// JoinTable creates the answer of a truth table input from its cells.
+// Cells which are not filled in with a 0 or 1 are marked as empty.
+func (i *Input) JoinTable(cell func(row int) string) string {
+	var sb strings.Builder
+	for r := range 1 << len(i.tableVars) {
+		switch c := strings.TrimSpace(cell(r)); c {
+		case "0", "1":
+			sb.WriteString(c)
+		default:
+			sb.WriteRune(emptyCell)
+		}
+	}
+	return sb.String()
+}
+
+// TableCell returns the content of the given cell of a truth table answer
+func TableCell(answer string, row int) string {
+	if row < 0 || row >= len(answer) || answer[row] == emptyCell {
+		return ""
+	}
+	return answer[row : row+1]
+}
+
+func (i *Input) initTruthTable() error {
+	if !i.IsTruthTable() {
+		if i.Vars != "" {
+			return errors.New("vars are only allowed at truth table inputs")
+		}
+		return nil
+	}
+
+	i.tableVars = nil
+	for _, v := range strings.Split(i.Vars, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return errors.New("empty variable name")
+		}
+		if err := checkIdent(v); err != nil {
+			return fmt.Errorf("invalid variable '%s': %w", v, err)
+		}
+		if slices.Contains(i.tableVars, v) {
+			return fmt.Errorf("variable '%s' is used twice", v)
+		}
+		i.tableVars = append(i.tableVars, v)
+	}
+	if len(i.tableVars) > maxTableVars {
+		return fmt.Errorf("at most %d variables are allowed", maxTableVars)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTruthTable(t *testing.T) {
+	tests := []struct {
+		expr string
+		vars []string
+		want string
+	}{
+		{"A&B", []string{"A", "B"}, "0001"},
+		{"A and B", []string{"A", "B"}, "0001"},
+		{"A|B", []string{"A", "B"}, "0111"},
+		{"A or B", []string{"A", "B"}, "0111"},
+		{"A xor B", []string{"A", "B"}, "0110"},
+		{"A nand B", []string{"A", "B"}, "1110"},
+		{"!A", []string{"A", "B"}, "1100"},
+		{"!(A|B)", []string{"A", "B"}, "1000"},
+		{"A&!B|C", []string{"A", "B", "C"}, "01011101"},
+		{"A|B&C", []string{"A", "B", "C"}, "00011111"},
+		{"1", []string{"A"}, "11"},
+		{"0110", []string{"A", "B"}, "0110"},
+		{"01_0", []string{"A", "B"}, "01_0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			table, err := truthTable(tt.expr, tt.vars)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, table)
+		})
+	}
+}
+
+func TestTruthTableError(t *testing.T) {
+	tests := []struct {
+		expr string
+		msg  string
+	}{
+		{"", "Die Eingabe ist leer!"},
+		{"A B", "Der Ausdruck 'A B' enthält Fehler und kann nicht analysiert werden!"},
+		{"A & C", "'C' kann nicht verwendet werden! Verfügbare Variablen sind: A, B"},
+		{"2", "Der Ausdruck '2' enthält Fehler und kann nicht analysiert werden!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := truthTable(tt.expr, []string{"A", "B"})
+			if assert.Error(t, err) {
+				assert.Equal(t, tt.msg, cleanupError(err))
+			}
+		})
+	}
+}
+
+func TestBoolFunctions(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result value.Value
+	}{
+		{`cmpBool("A&B","B and A",["A","B"])`, value.Bool(true)},
+		{`cmpBool("A xor B","A&!B|!A&B",["A","B"])`, value.Bool(true)},
+		{`cmpBool("A nand B","!A|!B",["A","B"])`, value.Bool(true)},
+		{`cmpBool("A&B","A|B",["A","B"])`, value.Bool(false)},
+		{`cmpBool("A&B","0001",["A","B"])`, value.Bool(true)},
+		{`cmpBool("A&B","0011",["A","B"])`, value.Bool(false)},
+		{`cmpBool("A&B","00_1",["A","B"])`, value.String("Die Wahrheitstabelle ist nicht vollständig ausgefüllt!")},
+		{`boolCplx("A|B","B|A",["A","B"])`, value.Bool(true)},
+		{`boolCplx("A|B","A|!A&B",["A","B"])`, value.String("Der Ausdruck ist zwar korrekt, aber nicht minimal!")},
+		{`boolCplx("A|B","A&B",["A","B"])`, value.String("Der Ausdruck ist nicht korrekt!")},
+		{`boolCplx("A&!B|!A&B","A xor B",["A","B"])`, value.String("Der Ausdruck ist zwar korrekt, aber keine disjunktive Normalform!")},
+		{`boolCplx("(A|B)&(!A|!B)","A&!B|!A&B",["A","B"])`, value.String("Der Ausdruck ist zwar korrekt, aber keine konjunktive Normalform!")},
+		{`boolCplx("(A|B)&(!A|!B)","(!B|!A)&(B|A)",["A","B"])`, value.Bool(true)},
+		{`boolCplx("A&B|C","(A|C)&(B|C)",["A","B","C"])`, value.String("Der Ausdruck ist zwar korrekt, aber keine disjunktive Normalform!")},
+		{`boolCplx("A&B","A and B",["A","B"])`, value.Bool(true)},
+		{`boolCplx("1","A|!A",["A"])`, value.String("Der Ausdruck ist zwar korrekt, aber nicht minimal!")},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.expr, func(t *testing.T) {
+			f, err := myParser.Generate(tst.expr)
+			assert.NoError(t, err)
+			if f != nil {
+				r, err := f.Eval()
+				assert.NoError(t, err)
+				assert.Equal(t, tst.result, r)
+			}
+		})
+	}
+}
+
+func TestBoolCplxInvalidExpected(t *testing.T) {
+	_, err := boolCplx("A xor B", "A&!B|!A&B", []string{"A", "B"})
+	assert.Error(t, err)
+}
+
+func truthTableLecture(vars, validator, test string) string {
+	return fmt.Sprintf(`<Lecture id="DT1">
+    <Title>Digitaltechnik</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task>
+            <Name>XOR</Name>
+            <Question>Füllen Sie die Wahrheitstabelle aus.</Question>
+            <Input id="Y" type="truthTable" vars="%s">
+                <Label>Y:</Label>
+                <Validator><Expression>%s</Expression>%s</Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`, vars, validator, test)
+}
+
+func TestTruthTableInput(t *testing.T) {
+	lecture, err := readLectureToTest(truthTableLecture("A, B", `cmpBool("A xor B",answer.Y,["A","B"])`,
+		`<Test Y="0110" ok="yes"/><Test Y="0111" ok="no"/>`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	in := lecture.Chapter[0].Task[0].Input[0]
+	assert.True(t, in.IsTruthTable())
+	assert.Equal(t, []string{"A", "B"}, in.TableVars())
+	assert.Equal(t, []TableRow{
+		{Index: 0, Values: []int{0, 0}},
+		{Index: 1, Values: []int{0, 1}},
+		{Index: 2, Values: []int{1, 0}},
+		{Index: 3, Values: []int{1, 1}},
+	}, in.TableRows())
+
+	cells := []string{"0", " 1", "", "x"}
+	answer := in.JoinTable(func(row int) string { return cells[row] })
+	assert.Equal(t, "01__", answer)
+	assert.Equal(t, "1", TableCell(answer, 1))
+	assert.Equal(t, "", TableCell(answer, 2))
+	assert.Equal(t, "", TableCell(answer, 4))
+}
+
+func TestTruthTableInputInit(t *testing.T) {
+	tests := []struct {
+		name string
+		vars string
+		msg  string
+	}{
+		{"empty", "", "empty variable name"},
+		{"twice", "A,A", "variable 'A' is used twice"},
+		{"invalid", "A,1B", "invalid variable '1B'"},
+		{"tooMany", "A,B,C,D,E,F", "at most 5 variables are allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(truthTableLecture(tt.vars, `cmpBool("A",answer.Y,["A"])`, ""))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"github.com/hneemann/quiz/mathml"
+	"log"
+	"math"
+	"math/cmplx"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// degree is the constant used to convert degrees to radians
+const degree = complex(math.Pi/180, 0)
+
+var complexParser = funcGen.New[complex128]().
+	SetComfort(true).
+	AddConstant("pi", complex(math.Pi, 0)).
+	AddConstant("e", complex(math.E, 0)).
+	AddConstant("j", 1i).
+	AddConstant("i", 1i).
+	AddConstant("deg", degree).
+	AddSimpleOp("∠", false, func(a, b complex128) (complex128, error) { return cmplx.Rect(real(a), real(b)), nil }).
+	AddSimpleOp("+", true, func(a, b complex128) (complex128, error) { return a + b, nil }).
+	AddSimpleOp("-", false, func(a, b complex128) (complex128, error) { return a - b, nil }).
+	AddSimpleOp("*", true, func(a, b complex128) (complex128, error) { return a * b, nil }).
+	AddSimpleOp("/", false, func(a, b complex128) (complex128, error) { return a / b, nil }).
+	AddSimpleOp("^", false, func(a, b complex128) (complex128, error) { return cmplx.Pow(a, b), nil }).
+	AddUnary("-", func(a complex128) (complex128, error) { return -a, nil }).
+	AddSimpleFunction("sin", cmplx.Sin).
+	AddSimpleFunction("cos", cmplx.Cos).
+	AddSimpleFunction("tan", cmplx.Tan).
+	AddSimpleFunction("exp", cmplx.Exp).
+	AddSimpleFunction("ln", cmplx.Log).
+	AddSimpleFunction("sqrt", cmplx.Sqrt).
+	AddSimpleFunction("abs", func(x complex128) complex128 { return complex(cmplx.Abs(x), 0) }).
+	AddSimpleFunction("arg", func(x complex128) complex128 { return complex(cmplx.Phase(x), 0) }).
+	AddSimpleFunction("re", func(x complex128) complex128 { return complex(real(x), 0) }).
+	AddSimpleFunction("im", func(x complex128) complex128 { return complex(imag(x), 0) }).
+	AddSimpleFunction("conj", cmplx.Conj).
+	SetNumberParser(
+		parser2.NumberParserFunc[complex128](
+			func(n string) (complex128, error) {
+				f, err := strconv.ParseFloat(n, 64)
+				return complex(f, 0), err
+			},
+		),
+	)
+
+// imagPrefix matches the electrical engineering notation j4 which means j*4
+var imagPrefix = regexp.MustCompile(`(^|[^\p{L}\p{N}_])([ij])([0-9])`)
+
+// prepareComplex converts the user input to an expression the complex parser understands
+func prepareComplex(expr string) string {
+	expr = strings.ReplaceAll(expr, "—", "-")
+	expr = strings.ReplaceAll(expr, "°", "*deg")
+	return imagPrefix.ReplaceAllString(expr, "$1$2*$3")
+}
+
+// Complex is a complex number in the validator language
+type Complex complex128
+
+var ComplexTypeId value.Type
+
+func (c Complex) GetType() value.Type {
+	return ComplexTypeId
+}
+
+func (c Complex) ToList() (*value.List, bool) {
+	return nil, false
+}
+
+func (c Complex) ToMap() (value.Map, bool) {
+	return value.Map{}, false
+}
+
+func (c Complex) ToInt() (int, bool) {
+	return 0, false
+}
+
+func (c Complex) ToFloat() (float64, bool) {
+	if imag(c) == 0 {
+		return real(c), true
+	}
+	return 0, false
+}
+
+func (c Complex) ToString(funcGen.Stack[value.Value]) (string, error) {
+	return formatComplex(complex128(c)), nil
+}
+
+func (c Complex) ToBool() (bool, bool) {
+	return false, false
+}
+
+func (c Complex) ToClosure() (funcGen.Function[value.Value], bool) {
+	return funcGen.Function[value.Value]{}, false
+}
+
+func formatComplex(c complex128) string {
+	re := strconv.FormatFloat(real(c), 'g', 6, 64)
+	if imag(c) == 0 {
+		return re
+	}
+	im := strconv.FormatFloat(math.Abs(imag(c)), 'g', 6, 64)
+	if real(c) == 0 {
+		if imag(c) < 0 {
+			return "-" + im + "j"
+		}
+		return im + "j"
+	}
+	if imag(c) < 0 {
+		return re + "-" + im + "j"
+	}
+	return re + "+" + im + "j"
+}
+
+func createComplexMethods() value.MethodMap {
+	return value.MethodMap{
+		"re": value.MethodAtType(0, func(c Complex, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			return value.Float(real(c)), nil
+		}),
+		"im": value.MethodAtType(0, func(c Complex, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			return value.Float(imag(c)), nil
+		}),
+		"abs": value.MethodAtType(0, func(c Complex, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			return value.Float(cmplx.Abs(complex128(c))), nil
+		}),
+		"arg": value.MethodAtType(0, func(c Complex, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			return value.Float(cmplx.Phase(complex128(c))), nil
+		}),
+		"string": value.MethodAtType(0, func(c Complex, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			return value.String(formatComplex(complex128(c))), nil
+		}),
+	}
+}
+
+// ComplexExpression is a complex valued expression given by the user
+type ComplexExpression struct {
+	expression string
+	fu         funcGen.Func[complex128]
+}
+
+var ComplexExpressionTypeId value.Type
+
+func (e ComplexExpression) GetType() value.Type {
+	return ComplexExpressionTypeId
+}
+
+func (e ComplexExpression) ToList() (*value.List, bool) {
+	return nil, false
+}
+
+func (e ComplexExpression) ToMap() (value.Map, bool) {
+	return value.Map{}, false
+}
+
+func (e ComplexExpression) ToInt() (int, bool) {
+	return 0, false
+}
+
+func (e ComplexExpression) ToFloat() (float64, bool) {
+	return 0, false
+}
+
+func (e ComplexExpression) ToString(funcGen.Stack[value.Value]) (string, error) {
+	return e.expression, nil
+}
+
+func (e ComplexExpression) ToBool() (bool, bool) {
+	return false, false
+}
+
+func (e ComplexExpression) ToClosure() (funcGen.Function[value.Value], bool) {
+	return funcGen.Function[value.Value]{}, false
+}
+
+func (e ComplexExpression) eval(args ...complex128) (complex128, error) {
+	r, err := e.fu.Eval(args...)
+	if err != nil {
+		return 0, GuiError{message: "Fehler bei der Berechnung von '" + e.expression + "'", cause: err}
+	}
+	return r, nil
+}
+
+func createComplexExpressionMethods(parser *parser2.Parser[complex128]) value.MethodMap {
+	return value.MethodMap{
+		"eval": value.MethodAtType(1, func(e ComplexExpression, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			if argList, ok := stack.Get(1).(*value.List); ok {
+				argValues, err := argList.ToSlice(stack)
+				if err != nil {
+					return nil, err
+				}
+				args := make([]complex128, len(argValues))
+				for i, v := range argValues {
+					c, err := toComplex(v)
+					if err != nil {
+						return nil, err
+					}
+					args[i] = c
+				}
+				r, err := e.eval(args...)
+				if err != nil {
+					return nil, err
+				}
+				return Complex(r), nil
+			} else {
+				return nil, fmt.Errorf("expected a list, got %v", stack.Get(1))
+			}
+		}),
+		"mathMl": value.MethodAtType(0, func(e ComplexExpression, stack funcGen.Stack[value.Value]) (value.Value, error) {
+			ast, err := parser.Parse(e.expression)
+			if err != nil {
+				return nil, GuiError{message: "Fehler im Ausdruck '" + e.expression + "'", cause: err}
+			}
+			a, err := MathMlFromAST(ast)
+			if err != nil {
+				return nil, err
+			}
+			sb := strings.Builder{}
+			sb.WriteString("<math xmlns='http://www.w3.org/1998/Math/MathML'>")
+			a.ToMathMl(&sb, nil)
+			sb.WriteString("</math>")
+			return value.String(sb.String()), nil
+		}),
+	}
+}
+
+func createComplexExpression(expr string, args []string) (value.Value, error) {
+	if len(expr) == 0 {
+		return nil, GuiError{message: "Die Eingabe ist leer!"}
+	}
+
+	prepared := prepareComplex(expr)
+
+	fu, err := complexParser.Generate(prepared, args...)
+	if err != nil {
+		log.Printf("error parsing complex expression '%s': %v", prepared, err)
+		return nil, GuiError{message: fmt.Sprintf("Der Ausdruck '%s' enthält Fehler und kann nicht analysiert werden!", expr), cause: err}
+	}
+	return ComplexExpression{expression: prepared, fu: fu}, nil
+}
+
+// toComplex converts a value to a complex number.
+// Strings are parsed as constant complex expressions.
+func toComplex(v value.Value) (complex128, error) {
+	switch v := v.(type) {
+	case Complex:
+		return complex128(v), nil
+	case value.String:
+		e, err := createComplexExpression(string(v), nil)
+		if err != nil {
+			return 0, err
+		}
+		return e.(ComplexExpression).eval()
+	default:
+		if f, ok := v.ToFloat(); ok {
+			return complex(f, 0), nil
+		}
+		return 0, fmt.Errorf("expected a complex number, got %v", v)
+	}
+}
+
+// cmpComplex compares two complex numbers.
+// The magnitude needs to match within the given percentage, and the
+// phase within the given number of degrees.
+func cmpComplex(expected, is complex128, percent, degrees float64) value.Value {
+	expAbs := cmplx.Abs(expected)
+	isAbs := cmplx.Abs(is)
+	if expAbs == 0 {
+		return value.Bool(isAbs < percent/100)
+	}
+
+	absOk := math.Abs((isAbs-expAbs)/expAbs*100) < percent
+
+	dif := math.Abs(math.Remainder(cmplx.Phase(is)-cmplx.Phase(expected), 2*math.Pi)) / real(degree)
+	phaseOk := dif < degrees
+
+	switch {
+	case absOk && phaseOk:
+		return value.Bool(true)
+	case absOk:
+		return value.String("Der Betrag ist richtig, aber die Phase stimmt nicht!")
+	case phaseOk:
+		return value.String("Die Phase ist richtig, aber der Betrag stimmt nicht!")
+	default:
+		return value.Bool(false)
+	}
+}
+
+func complexArgs(stack funcGen.Stack[value.Value]) (complex128, complex128, error) {
+	expected, err := toComplex(stack.Get(0))
+	if err != nil {
+		return 0, 0, err
+	}
+	is, err := toComplex(stack.Get(1))
+	if err != nil {
+		return 0, 0, err
+	}
+	return expected, is, nil
+}
+
+func addComplexFunctions(f *funcGen.FunctionGenerator[value.Value]) {
+	f.AddStaticFunction("parseComplexFunc", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			exp, ok := stack.Get(0).(value.String)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %v", stack.Get(0))
+			}
+			list, ok := stack.Get(1).(*value.List)
+			if !ok {
+				return nil, fmt.Errorf("expected a list, got %v", stack.Get(1))
+			}
+			argValues, err := list.ToSlice(stack)
+			if err != nil {
+				return nil, err
+			}
+			var args []string
+			for _, v := range argValues {
+				if str, ok := v.(value.String); ok {
+					args = append(args, string(str))
+				} else {
+					return nil, fmt.Errorf("expected string, got %v", v)
+				}
+			}
+			return createComplexExpression(string(exp), args)
+		},
+		Args:   2,
+		IsPure: true,
+	}.SetDescription("strFunc", "listOfArgs", "parse a complex valued function using the list of arguments"))
+	f.AddStaticFunction("complex", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			re, ok := stack.Get(0).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(0))
+			}
+			im, ok := stack.Get(1).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(1))
+			}
+			return Complex(complex(re, im)), nil
+		},
+		Args:   2,
+		IsPure: true,
+	}.SetDescription("re", "im", "creates a complex number"))
+	f.AddStaticFunction("cmpComplex", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, is, err := complexArgs(stack)
+			if err != nil {
+				return nil, err
+			}
+			percent, ok := stack.Get(2).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(2))
+			}
+			// a phase error of phi radians causes a relative error of about phi
+			return cmpComplex(expected, is, percent, percent/100/real(degree)), nil
+		},
+		Args:   3,
+		IsPure: true,
+	}.SetDescription("expected", "is", "percent",
+		"compares two complex numbers. The magnitude has to match within the given percentage and "+
+			"the phase within the corresponding angle in radians (percent/100)"))
+	f.AddStaticFunction("cmpComplexPolar", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, is, err := complexArgs(stack)
+			if err != nil {
+				return nil, err
+			}
+			percent, ok := stack.Get(2).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(2))
+			}
+			degrees, ok := stack.Get(3).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(3))
+			}
+			return cmpComplex(expected, is, percent, degrees), nil
+		},
+		Args:   4,
+		IsPure: true,
+	}.SetDescription("expected", "is", "percent", "degrees",
+		"compares two complex numbers. The magnitude has to match within the given percentage and "+
+			"the phase within the given number of degrees"))
+}
+
+func complexToMathMl(c complex128) mathml.Ast {
+	if c == degree {
+		return mathml.SimpleOperator("°")
+	}
+	if imag(c) == 0 {
+		return mathml.SimpleNumber(fmt.Sprintf("%.6g", real(c)))
+	}
+	var im mathml.Ast = mathml.SimpleIdent("j")
+	if math.Abs(imag(c)) != 1 {
+		im = mathml.NewRow(mathml.SimpleNumber(fmt.Sprintf("%.6g", math.Abs(imag(c)))), im)
+	}
+	if real(c) == 0 {
+		if imag(c) < 0 {
+			return mergeRow(mathml.SimpleOperator("-"), im)
+		}
+		return im
+	}
+	op := "+"
+	if imag(c) < 0 {
+		op = "-"
+	}
+	return mergeRow(mathml.SimpleNumber(fmt.Sprintf("%.6g", real(c))), mathml.SimpleOperator(op), im)
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/cmplx"
+	"strings"
+	"testing"
+)
+
+func TestComplexParser(t *testing.T) {
+	tests := []struct {
+		expr string
+		want complex128
+	}{
+		{"3+4j", 3 + 4i},
+		{"3+j4", 3 + 4i},
+		{"3-i4", 3 - 4i},
+		{"2*j", 2i},
+		{"5∠0", 5},
+		{"2∠90°", 2i},
+		{"abs(3+4j)", 5},
+		{"re(3+4j)", 3},
+		{"im(3+4j)", 4},
+		{"conj(3+4j)", 3 - 4i},
+		{"arg(j)", complex(math.Pi/2, 0)},
+		{"1/(1+j)", 0.5 - 0.5i},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := createComplexExpression(tt.expr, nil)
+			assert.NoError(t, err)
+			c, err := e.(ComplexExpression).eval()
+			assert.NoError(t, err)
+			assert.InDelta(t, 0, cmplx.Abs(c-tt.want), 1e-9)
+		})
+	}
+}
+
+func TestComplexFunctions(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result value.Value
+	}{
+		{"cmpComplex(\"3+4j\",\"5∠53.13°\",1)", value.Bool(true)},
+		{"cmpComplex(complex(3,4),\"3+j4\",1)", value.Bool(true)},
+		{"cmpComplex(\"3+4j\",\"5∠-53.13°\",1)", value.String("Der Betrag ist richtig, aber die Phase stimmt nicht!")},
+		{"cmpComplex(\"3+4j\",\"6∠53.13°\",1)", value.String("Die Phase ist richtig, aber der Betrag stimmt nicht!")},
+		{"cmpComplex(\"3+4j\",\"1\",1)", value.Bool(false)},
+		{"cmpComplexPolar(\"3+4j\",\"5∠55°\",1,2)", value.Bool(true)},
+		{"cmpComplexPolar(\"3+4j\",\"5∠56°\",1,2)", value.String("Der Betrag ist richtig, aber die Phase stimmt nicht!")},
+		{"parseComplexFunc(\"3+4j\",[]).eval([]).abs()", value.Float(5)},
+		{"parseComplexFunc(\"3+4j\",[]).eval([]).string()", value.String("3+4j")},
+		{"parseComplexFunc(\"x*j\",[\"x\"]).eval([2]).im()", value.Float(2)},
+		{"parseComplexFunc(\"x*j\",[\"x\"]).eval([complex(0,1)]).re()", value.Float(-1)},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.expr, func(t *testing.T) {
+			f, err := myParser.Generate(tst.expr)
+			assert.NoError(t, err)
+			if f != nil {
+				r, err := f.Eval()
+				assert.NoError(t, err)
+				assert.Equal(t, tst.result, r)
+			}
+		})
+	}
+}
+
+func TestComplexMathMl(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "3+4j", want: "<mrow><mn>3</mn><mo>+</mo><mn>4</mn><mo>*</mo><mi>j</mi></mrow>"},
+		{input: "5∠53°", want: "<mrow><mn>5</mn><mo>∠</mo><mn>53</mn><mo>°</mo></mrow>"},
+		{input: "5∠-53°", want: "<mrow><mn>5</mn><mo>∠</mo><mo>-</mo><mn>53</mn><mo>°</mo></mrow>"},
+		{input: "conj(z)", want: "<mrow><mi>conj</mi><mo>(</mo><mi>z</mi><mo>)</mo></mrow>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			a, err := complexParser.GetParser().Parse(prepareComplex(tt.input))
+			assert.NoError(t, err)
+			ml, err := MathMlFromAST(a)
+			assert.NoError(t, err)
+			sb := strings.Builder{}
+			ml.ToMathMl(&sb, nil)
+			assert.Equal(t, tt.want, sb.String())
+		})
+	}
+}
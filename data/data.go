@@ -25,6 +25,10 @@ const (
 	Checkbox InputType = iota
 	Text
 	Number
+	Radio
+	Select
+	TruthTable
+	Matrix
 )
 
 func (it *InputType) UnmarshalText(text []byte) error {
@@ -33,6 +37,14 @@ func (it *InputType) UnmarshalText(text []byte) error {
 		*it = Number
 	case "checkbox":
 		*it = Checkbox
+	case "radio":
+		*it = Radio
+	case "select":
+		*it = Select
+	case "truthtable":
+		*it = TruthTable
+	case "matrix":
+		*it = Matrix
 	default:
 		*it = Text
 	}
@@ -46,6 +58,14 @@ func (it InputType) MarshalText() ([]byte, error) {
 		name = "number"
 	case Checkbox:
 		name = "checkbox"
+	case Radio:
+		name = "radio"
+	case Select:
+		name = "select"
+	case TruthTable:
+		name = "truthTable"
+	case Matrix:
+		name = "matrix"
 	default:
 		name = "text"
 	}
@@ -83,15 +103,35 @@ func (t *Test) String() string {
 	return b.String()
 }
 
-func (t *Test) test(fu funcGen.Func[value.Value], avail map[InputId]InputType) error {
+func (t *Test) test(val *Validator, avail map[InputId]InputType, params ParamList) error {
 	m := DataMap{}
+	fixed := map[string]float64{}
 	var expectedOkStr string
+	var expectedScoreStr string
+	var expectedMistake string
+	hasMistake := false
+	expectedMessages := map[InputId]string{}
 	for k, v := range t.data {
-		if k != "ok" {
+		if name, isParam := strings.CutPrefix(string(k), "param."); isParam {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("attribute '%s' needs to be a number, not '%s'", k, v)
+			}
+			fixed[name] = f
+		} else if id, isMessage := strings.CutPrefix(string(k), "message."); isMessage {
+			expectedMessages[InputId(id)] = v
+		} else if k == "score" {
+			expectedScoreStr = v
+		} else if k == "mistake" {
+			expectedMistake = v
+			hasMistake = true
+		} else if k != "ok" {
 			if ty, ok := avail[k]; ok {
 				switch ty {
-				case Number, Text:
+				case Number, Text, Radio, Select, TruthTable:
 					m[k] = v
+				case Matrix:
+					m[k] = parseMatrixTest(v)
 				case Checkbox:
 					switch v {
 					case "yes", "true":
@@ -110,21 +150,70 @@ func (t *Test) test(fu funcGen.Func[value.Value], avail map[InputId]InputType) e
 		}
 
 	}
-	v, err := fu.Eval(value.NewMap(m))
+
+	p, err := params.create("test", fixed)
 	if err != nil {
 		return err
 	}
 
-	if expectedOkStr != "" {
-		expectedOk := false
-		if expectedOkStr == "yes" {
-			expectedOk = true
-		} else if expectedOkStr != "no" {
-			return fmt.Errorf("attribute 'ok' needs to be yes or no, not '%s'", expectedOkStr)
+	if hasMistake {
+		score, _, mistake := val.check(value.NewMap(m), p.toMap())
+		if expectedMistake == "" {
+			if mistake != nil {
+				return fmt.Errorf("expected no mistake, got '%s'", mistake.Id)
+			}
+		} else {
+			if val.Mistake.get(expectedMistake) == nil {
+				return fmt.Errorf("mistake '%s' is not defined", expectedMistake)
+			}
+			if score >= 1 {
+				return fmt.Errorf("expected mistake '%s', but the answer is correct", expectedMistake)
+			}
+			if mistake == nil {
+				return fmt.Errorf("expected mistake '%s', got none", expectedMistake)
+			}
+			if mistake.Id != expectedMistake {
+				return fmt.Errorf("expected mistake '%s', got '%s'", expectedMistake, mistake.Id)
+			}
 		}
+		if expectedScoreStr == "" && expectedOkStr == "" {
+			return nil
+		}
+	}
 
-		if isOk, ok := v.(value.Bool); ok {
-			if bool(isOk) != expectedOk {
+	v, err := val.eval(value.NewMap(m), p.toMap())
+	if err != nil {
+		return err
+	}
+
+	if mv, isMap := v.(value.Map); isMap {
+		return testMap(mv, expectedMessages, expectedOkStr, expectedScoreStr)
+	}
+	if len(expectedMessages) > 0 {
+		return fmt.Errorf("expected map, got %T", v)
+	}
+
+	if expectedScoreStr != "" {
+		expectedScore, err := strconv.ParseFloat(expectedScoreStr, 64)
+		if err != nil {
+			return fmt.Errorf("attribute 'score' needs to be a number, not '%s'", expectedScoreStr)
+		}
+		if score, ok := resultScore(v); ok {
+			if math.Abs(score-expectedScore) > 1e-6 {
+				return fmt.Errorf("expected score %g, got %g", expectedScore, score)
+			}
+		} else {
+			return fmt.Errorf("expected score, got %T", v)
+		}
+	} else if expectedOkStr != "" {
+		expectedOk, err := parseOk(expectedOkStr)
+		if err != nil {
+			return err
+		}
+
+		if score, ok := resultScore(v); ok {
+			isOk := score >= 1
+			if isOk != expectedOk {
 				return fmt.Errorf("expected %t, got %t", expectedOk, isOk)
 			}
 		} else {
@@ -143,52 +232,151 @@ func (t *Test) test(fu funcGen.Func[value.Value], avail map[InputId]InputType) e
 	return nil
 }
 
+func parseOk(ok string) (bool, error) {
+	switch ok {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("attribute 'ok' needs to be yes or no, not '%s'", ok)
+	}
+}
+
+// testMap checks the map returned by a task validator.
+// The expected messages are given by the attributes 'message.[id]'.
+func testMap(m value.Map, expectedMessages map[InputId]string, expectedOkStr, expectedScoreStr string) error {
+	if expectedScoreStr != "" {
+		return fmt.Errorf("attribute 'score' is not supported if a map is returned")
+	}
+	messages, _, err := mapMessages(m)
+	if err != nil {
+		return err
+	}
+	for id, expected := range expectedMessages {
+		if messages[id] != expected {
+			return fmt.Errorf("expected '%s' at '%s', got '%s'", expected, id, messages[id])
+		}
+	}
+	if expectedOkStr != "" {
+		expectedOk, err := parseOk(expectedOkStr)
+		if err != nil {
+			return err
+		}
+		isOk := len(messages) == 0
+		if isOk != expectedOk {
+			return fmt.Errorf("expected %t, got %t", expectedOk, isOk)
+		}
+	} else if len(expectedMessages) == 0 {
+		return fmt.Errorf("expected string, got map")
+	}
+	return nil
+}
+
 type Validator struct {
 	Expression  string
 	Help        string
 	Explanation string
 	Test        []Test
+	Mistake     MistakeList
+	FollowUp    source
 	fu          funcGen.Func[value.Value]
+	followUp    funcGen.Func[value.Value]
+	followUpVar map[InputId]bool
+	fns         *functions
+	pos         position
+	exprPos     position
 }
 
 type collectVars struct {
-	used map[InputId]bool
+	used  map[InputId]bool
+	param map[string]bool
+}
+
+func newCollectVars() *collectVars {
+	return &collectVars{used: make(map[InputId]bool), param: make(map[string]bool)}
 }
 
 func (c *collectVars) Visit(ast parser2.AST) bool {
 	if a, ok := ast.(*parser2.MapAccess); ok {
 		if i, ok := a.MapValue.(*parser2.Ident); ok {
-			if i.Name == "answer" {
+			switch i.Name {
+			case "answer":
 				c.used[InputId(a.Key)] = true
+			case "param":
+				c.param[a.Key] = true
 			}
 		}
 	}
 	return true
 }
 
+// compile compiles an additional expression used by a validator.
+// The variables used by the expression are returned.
+func (s source) compile(what string, varsAvail map[InputId]InputType, params ParamList, fns *functions) (funcGen.Func[value.Value], *collectVars, error) {
+	f, err := myParser.Generate(s.text, fns.argNames("answer", "param")...)
+	if err != nil {
+		return nil, nil, s.pos.exprError(err)
+	}
+
+	a, err := myParser.GetParser().Parse(s.text)
+	if err != nil {
+		return nil, nil, s.pos.exprError(err)
+	}
+	if err := fns.checkCalls(a); err != nil {
+		return nil, nil, s.pos.errorf("%w", err)
+	}
+	varsUsed := newCollectVars()
+	a.Traverse(varsUsed)
+	for vu := range varsUsed.used {
+		if _, ok := varsAvail[vu]; !ok {
+			return nil, nil, s.pos.errorf("'%s' is used in %s but not available", vu, what)
+		}
+	}
+	for pu := range varsUsed.param {
+		if params.get(pu) == nil {
+			return nil, nil, s.pos.errorf("parameter '%s' is used in %s but not defined", pu, what)
+		}
+	}
+	return f, varsUsed, nil
+}
+
 // Init initializes the validator.
 // If thisVar is not empty, it has to be a used in the expression.
 // The vars map contains all variables that can be used in the expression.
-func (v *Validator) init(varsAvail map[InputId]InputType, mustBeUsed []InputId) error {
+// The params list contains the task parameters available as param.*.
+// The fns are the functions defined in the lecture.
+func (v *Validator) init(varsAvail map[InputId]InputType, mustBeUsed []InputId, params ParamList, fns *functions) error {
 	if strings.TrimSpace(v.Expression) == "" {
 		return fmt.Errorf("no expression given")
 	}
 
 	v.Help = cleanUpMarkdown(v.Help)
+	if err := params.checkRefs(v.Help, "help"); err != nil {
+		return err
+	}
+	if err := params.checkRefs(v.Explanation, "explanation"); err != nil {
+		return err
+	}
 
-	f, err := myParser.Generate(v.Expression, "answer")
+	f, err := myParser.Generate(v.Expression, fns.argNames("answer", "param")...)
 	if err != nil {
-		return err
+		return v.exprPos.exprError(err)
 	}
 	v.fu = f
+	v.fns = fns
 
 	a, err := myParser.GetParser().Parse(v.Expression)
 	if err != nil {
-		return err
+		return v.exprPos.exprError(err)
+	}
+
+	if err := fns.checkCalls(a); err != nil {
+		return v.exprPos.errorf("%w", err)
 	}
 
-	varsUsed := collectVars{used: make(map[InputId]bool)}
-	a.Traverse(&varsUsed)
+	varsUsed := newCollectVars()
+	a.Traverse(varsUsed)
 
 	if len(varsUsed.used) == 0 {
 		return fmt.Errorf("no variable is used")
@@ -200,14 +388,33 @@ func (v *Validator) init(varsAvail map[InputId]InputType, mustBeUsed []InputId)
 		}
 	}
 
+	for pu := range varsUsed.param {
+		if params.get(pu) == nil {
+			return fmt.Errorf("parameter '%s' is used but not defined", pu)
+		}
+	}
+
 	for _, va := range mustBeUsed {
 		if !varsUsed.used[va] {
 			return fmt.Errorf("'%s' is not used in expression", va)
 		}
 	}
 
+	if err := v.Mistake.init(varsAvail, params, fns); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(v.FollowUp.text) != "" {
+		f, used, err := v.FollowUp.compile("follow-up expression", varsAvail, params, fns)
+		if err != nil {
+			return err
+		}
+		v.followUp = f
+		v.followUpVar = used.used
+	}
+
 	for _, t := range v.Test {
-		err = t.test(v.fu, varsAvail)
+		err = t.test(v, varsAvail, params)
 		if err != nil {
 			return fmt.Errorf("error in test <test %s>: %w", t.String(), err)
 		}
@@ -241,37 +448,122 @@ func cleanupError(err error) string {
 
 const DefaultMessage = "Das ist nicht richtig!"
 
-func (v *Validator) Validate(m value.Map) (bool, string) {
+// resultScore converts the result of a validator to a score in the range [0,1].
+// A boolean result is mapped to 0 or 1, numbers are clamped to [0,1].
+func resultScore(r value.Value) (float64, bool) {
+	switch r := r.(type) {
+	case value.Bool:
+		if r {
+			return 1, true
+		}
+		return 0, true
+	case value.Float, value.Int:
+		f, _ := r.ToFloat()
+		return math.Max(0, math.Min(1, f)), true
+	default:
+		return 0, false
+	}
+}
+
+func (v *Validator) Validate(answer, param value.Map) (bool, string) {
+	score, msg := v.Score(answer, param)
+	return score >= 1, msg
+}
+
+// Score validates the answer and returns a score in the range [0,1].
+// If the score is less than one, a message is returned.
+func (v *Validator) Score(answer, param value.Map) (float64, string) {
+	score, msg, _ := v.check(answer, param)
+	return score, msg
+}
+
+// check validates the answer. If the answer is not correct, the
+// mistakes are checked and the first matching mistake is returned.
+func (v *Validator) check(answer, param value.Map) (float64, string, *Mistake) {
 	if v == nil {
-		return true, ""
+		return 1, "", nil
 	}
 
-	r, err := v.fu.Eval(m)
+	r, err := v.eval(answer, param)
+	return v.judge(r, err, answer, param)
+}
+
+// judge creates the score and the message from the result of the
+// validator expression.
+func (v *Validator) judge(r value.Value, err error, answer, param value.Map) (float64, string, *Mistake) {
 	if err != nil {
-		return false, cleanupError(err)
+		return 0, cleanupError(err), nil
 	}
-	switch r := r.(type) {
-	case value.Bool:
-		if r {
-			return true, ""
+	score := 0.0
+	var msg string
+	if str, ok := r.(value.String); ok {
+		msg = string(str)
+	} else {
+		score, ok = resultScore(r)
+		if !ok {
+			return 0, "unexpected result", nil
+		}
+		if score >= 1 {
+			return 1, "", nil
+		}
+		if score > 0 {
+			msg = fmt.Sprintf("Das ist teilweise richtig (%d%%)!", int(math.Round(score*100)))
 		} else {
-			if v.Help == "" {
-				return false, DefaultMessage
+			msg = DefaultMessage
+		}
+	}
+	m := v.Mistake.find(v.fns, answer, param)
+	if m != nil {
+		msg = m.Message
+	}
+	return score, v.withHelp(msg), m
+}
+
+// eval evaluates the validator expression
+func (v *Validator) eval(answer, param value.Value) (value.Value, error) {
+	return v.fu.Eval(v.fns.args(answer, param)...)
+}
+
+func (v *Validator) withHelp(msg string) string {
+	if v.Help == "" {
+		return msg
+	}
+	return msg + "\n\nHinweis: " + v.Help
+}
+
+// ToResultMap validates the answer and adds the message to the result.
+// The judged inputs are the inputs whose correctness is determined by
+// this validator. If all of them are correct, they are marked as accepted.
+// A task validator can also return a map containing messages for the
+// judged inputs, see mapResult.
+// The score and the detected mistake, if any, are returned.
+func (v *Validator) ToResultMap(answer value.Map, params Params, id InputId, judged []*Input, result *Result, showResult bool) (float64, *Mistake) {
+	p := params.toMap()
+	r, err := v.eval(answer, p)
+
+	var score float64
+	var msg string
+	var mistake *Mistake
+	if m, isMap := r.(value.Map); isMap && err == nil && id == "_task_" {
+		score, msg = v.mapResult(m, judged, params, result)
+		if score < 1 {
+			if mistake = v.Mistake.find(v.fns, answer, p); mistake != nil {
+				msg = mistake.Message
+			}
+			if msg != "" {
+				msg = v.withHelp(msg)
 			}
-			return false, DefaultMessage + "\n\nHinweis: " + v.Help
 		}
-	case value.String:
-		if v.Help == "" {
-			return false, string(r)
+	} else {
+		score, msg, mistake = v.judge(r, err, answer, p)
+		if score >= 1 {
+			for _, i := range judged {
+				result.accepted[i.Id] = true
+			}
 		}
-		return false, string(r) + "\n\nHinweis: " + v.Help
-	default:
-		return false, "unexpected result"
 	}
-}
 
-func (v *Validator) ToResultMap(m value.Map, id InputId, result map[InputId]string, showResult bool) {
-	if ok, msg := v.Validate(m); !ok {
+	if score < 1 {
 		if showResult {
 			if v.Explanation != "" {
 				if msg != "" {
@@ -280,8 +572,11 @@ func (v *Validator) ToResultMap(m value.Map, id InputId, result map[InputId]stri
 				msg += "Lösung:\n\n" + v.Explanation
 			}
 		}
-		result[id] = msg
+		if msg != "" {
+			result.Messages[id] = params.Substitute(msg)
+		}
 	}
+	return score, mistake
 }
 
 type (
@@ -315,22 +610,148 @@ func NewChapterNum(s string) (ChapterNum, error) {
 	return c, nil
 }
 
+// Option is a selectable option of a radio or select input
+type Option struct {
+	Value string `xml:"value,attr"`
+	Label string `xml:",chardata"`
+}
+
 type Input struct {
 	Id        InputId `xml:"id,attr"`
 	Label     string
 	Type      InputType `xml:"type,attr"`
+	Shuffle   bool      `xml:"shuffle,attr"`
+	Option    []*Option
 	Validator *Validator
+	Hint      HintList
+	Points    float64 `xml:"points,attr"`
+	FollowUp  string  `xml:"followUp,attr"`
+	// Vars contains the comma separated variables of a truth table
+	Vars string `xml:"vars,attr"`
+	// Rows and Cols contain the size of a matrix
+	Rows      int `xml:"rows,attr"`
+	Cols      int `xml:"cols,attr"`
+	dependsOn []InputId
+	tableVars []string
+	pos       position
+}
+
+// weight returns the weight of the input used to calculate the task score
+func (i *Input) weight() float64 {
+	if i.Points > 0 {
+		return i.Points
+	}
+	return 1
+}
+
+func (i *Input) IsCheckbox() bool {
+	return i.Type == Checkbox
+}
+
+func (i *Input) IsRadio() bool {
+	return i.Type == Radio
+}
+
+func (i *Input) IsSelect() bool {
+	return i.Type == Select
+}
+
+func (i *Input) hasOptions() bool {
+	return i.Type == Radio || i.Type == Select
+}
+
+func (i *Input) hasOption(v string) bool {
+	for _, o := range i.Option {
+		if o.Value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Options returns the options of the input.
+// If shuffling is enabled, the order of the options depends on the given seed.
+func (i *Input) Options(seed string) []*Option {
+	if !i.Shuffle {
+		return i.Option
+	}
+	r := newRand(seed + string(i.Id))
+	o := make([]*Option, len(i.Option))
+	copy(o, i.Option)
+	r.Shuffle(len(o), func(a, b int) {
+		o[a], o[b] = o[b], o[a]
+	})
+	return o
+}
+
+func (i *Input) initOptions(params ParamList) error {
+	if !i.hasOptions() {
+		if len(i.Option) > 0 {
+			return errors.New("options are only allowed at radio or select inputs")
+		}
+		return nil
+	}
+
+	if len(i.Option) < 2 {
+		return errors.New("at least two options are required")
+	}
+	values := map[string]bool{}
+	for _, o := range i.Option {
+		if o.Value == "" {
+			return errors.New("option without a value")
+		}
+		if values[o.Value] {
+			return fmt.Errorf("duplicate option value '%s'", o.Value)
+		}
+		values[o.Value] = true
+		o.Label = cleanUpMarkdown(o.Label)
+		if o.Label == "" {
+			return fmt.Errorf("no label at option '%s'", o.Value)
+		}
+		if err := params.checkRefs(o.Label, "option label"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOptions checks if the values used in the tests are valid options
+func (v *Validator) checkOptions(inputs []*Input) error {
+	if v == nil {
+		return nil
+	}
+	for _, t := range v.Test {
+		for _, i := range inputs {
+			if val, ok := t.data[i.Id]; ok && i.hasOptions() && val != "" && !i.hasOption(val) {
+				return fmt.Errorf("'%s' is not an option of '%s' in test <test %s>", val, i.Id, t.String())
+			}
+		}
+	}
+	return nil
 }
 
 type Task struct {
 	chapter           *Chapter
+	pool              *Pool
+	template          *UseTemplate
+	pos               position
 	num               TaskNum
 	tid               TaskId
+	oldIds            []TaskId
+	hashId            TaskId
 	inputHasValidator map[InputId]bool
+	Id                TaskId  `xml:"id,attr"`
+	Points            float64 `xml:"points,attr"`
+	MaxAttempts       int     `xml:"maxAttempts,attr"`
+	HintPenalty       float64 `xml:"hintPenalty,attr"`
+	OldId             []TaskId
 	Name              string
+	Tag               []string
 	Question          string
+	Param             ParamList
 	Input             []*Input
 	Validator         *Validator
+	Hint              HintList
 }
 
 func (t *Task) Chapter() *Chapter {
@@ -345,6 +766,16 @@ func (t *Task) TID() TaskId {
 	return t.tid
 }
 
+// CreateParams creates the task parameters.
+// The seed is used to select the random parameter values, so
+// that the same seed always results in the same values.
+func (t *Task) CreateParams(seed string) (Params, error) {
+	if len(t.Param) == 0 {
+		return nil, nil
+	}
+	return t.Param.create(seed+string(t.tid), nil)
+}
+
 func (t *Task) InputHasValidator(id InputId) bool {
 	if has, ok := t.inputHasValidator[id]; ok {
 		return has
@@ -354,12 +785,22 @@ func (t *Task) InputHasValidator(id InputId) bool {
 
 type Chapter struct {
 	Include       string `xml:"file,attr"`
+	Id            string `xml:"id,attr"`
+	Requires      string `xml:"requires,attr"`
+	requires      []*Requirement
 	lecture       *Lecture
+	pos           position
 	num           ChapterNum
 	StepByStep    bool `xml:"stepByStep,attr"`
+	MaxAttempts   int  `xml:"maxAttempts,attr"`
+	ExamMinutes   int  `xml:"examMinutes,attr"`
 	Title         string
 	Description   string
+	Functions     []*Functions
+	TaskTemplate  []*TaskTemplate
 	Task          []*Task
+	UseTemplate   []*UseTemplate
+	Pool          []*Pool
 	Chapter       ChapterList
 	ParentChapter *Chapter
 }
@@ -372,17 +813,6 @@ func (c *Chapter) Num() ChapterNum {
 	return c.num
 }
 
-func (c *Chapter) Tasks() int {
-	if c.HasSubChapter() {
-		n := 0
-		for _, ch := range c.Chapter {
-			n += ch.Tasks()
-		}
-		return n
-	}
-	return len(c.Task)
-}
-
 func (c *Chapter) GetTask(tid TaskNum) (*Task, error) {
 	if tid < 0 || int(tid) >= len(c.Task) {
 		return nil, fmt.Errorf("task %d not found", tid)
@@ -391,7 +821,7 @@ func (c *Chapter) GetTask(tid TaskNum) (*Task, error) {
 }
 
 func (c *Chapter) IsEmpty() bool {
-	return len(c.Task) == 0 && c.Description == "" && c.Title == ""
+	return len(c.Task) == 0 && len(c.Pool) == 0 && len(c.UseTemplate) == 0 && len(c.TaskTemplate) == 0 && c.Description == "" && c.Title == "" && len(c.Functions) == 0
 }
 
 func (c *Chapter) HasSubChapter() bool {
@@ -407,14 +837,26 @@ func (c *Chapter) FullTitle() string {
 
 func (c *Chapter) init(cnum ChapterNum, l *Lecture) error {
 	if c.Title == "" {
-		return fmt.Errorf("no title in chapter %d", cnum)
+		return c.pos.errorf("no title in chapter %d", cnum)
 	}
 	c.lecture = l
 	c.num = cnum
 	c.Description = cleanUpMarkdown(c.Description)
 
+	if c.MaxAttempts < 0 {
+		return c.pos.errorf("negative maxAttempts in chapter '%s'", c.Title)
+	}
+
+	if err := c.initExam(); err != nil {
+		return err
+	}
+
+	if err := c.initPools(); err != nil {
+		return err
+	}
+
 	if len(c.Task) > 0 && c.HasSubChapter() {
-		return fmt.Errorf("chapter '%s' contains both tasks and subchapters", c.Title)
+		return c.pos.errorf("chapter '%s' contains both tasks and subchapters", c.Title)
 	}
 
 	if c.HasSubChapter() {
@@ -427,74 +869,160 @@ func (c *Chapter) init(cnum ChapterNum, l *Lecture) error {
 		}
 	} else {
 		for tNum, task := range c.Task {
-			task.chapter = c
-			task.num = TaskNum(tNum)
-			task.Question = cleanUpMarkdown(task.Question)
-
-			if task.Name == "" {
-				task.Name = fmt.Sprintf("Frage %d", tNum+1)
-			} else {
-				task.Name = fmt.Sprintf("Frage %d: %s", tNum+1, task.Name)
+			if err := c.initTask(TaskNum(tNum), task, l); err != nil {
+				if task.template != nil {
+					return task.template.pos.errorf("error in task created from template '%s': %w", task.template.Name, err)
+				}
+				return err
 			}
+		}
+		if err := c.initPoolIds(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if len(task.Input) == 0 {
-				return fmt.Errorf("no input in chapter '%s' task '%s'", c.Title, task.Name)
-			}
+// initTask initializes a task of this chapter
+func (c *Chapter) initTask(tNum TaskNum, task *Task, l *Lecture) error {
+	task.chapter = c
+	task.num = tNum
+	task.Question = cleanUpMarkdown(task.Question)
 
-			vars := make(map[InputId]InputType)
-			for _, i := range task.Input {
-				i.Label = cleanUpMarkdown(i.Label)
+	if task.Name == "" {
+		task.Name = fmt.Sprintf("Frage %d", tNum+1)
+	} else {
+		task.Name = fmt.Sprintf("Frage %d: %s", tNum+1, task.Name)
+	}
 
-				if i.Id == "" {
-					return fmt.Errorf("no id at input in chapter '%s' task '%s'", c.Title, task.Name)
-				}
+	if len(task.Input) == 0 {
+		return task.pos.errorf("no input in chapter '%s' task '%s'", c.Title, task.Name)
+	}
 
-				if err := checkIdent(string(i.Id)); err != nil {
-					return fmt.Errorf("invalid id '%s' at input in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
-				}
+	if task.Points < 0 {
+		return task.pos.errorf("negative points in chapter '%s' task '%s'", c.Title, task.Name)
+	}
+	if task.MaxAttempts < 0 {
+		return task.pos.errorf("negative maxAttempts in chapter '%s' task '%s'", c.Title, task.Name)
+	}
+	if err := task.initTags(); err != nil {
+		return err
+	}
 
-				if _, ok := vars[i.Id]; ok {
-					return fmt.Errorf("duplicate input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
-				}
-				vars[i.Id] = i.Type
+	err := task.Param.init()
+	if err != nil {
+		return task.pos.errorf("invalid parameter in chapter '%s' task '%s': %w", c.Title, task.Name, err)
+	}
+	if err := task.Param.checkRefs(task.Question, "question"); err != nil {
+		return task.pos.errorf("error in chapter '%s' task '%s': %w", c.Title, task.Name, err)
+	}
+	if err := task.Hint.init(task.Param); err != nil {
+		return task.pos.errorf("invalid hint in chapter '%s' task '%s': %w", c.Title, task.Name, err)
+	}
+	if task.HintPenalty < 0 || task.HintPenalty > 1 {
+		return task.pos.errorf("hintPenalty needs to be in the range [0,1] in chapter '%s' task '%s'", c.Title, task.Name)
+	}
 
-				if i.Label == "" {
-					return fmt.Errorf("no label at input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
-				}
-			}
+	vars := make(map[InputId]InputType)
+	for _, i := range task.Input {
+		i.Label = cleanUpMarkdown(i.Label)
 
-			hasValidator := make(map[InputId]bool)
-			var needsToBeUsedInTaskValidator []InputId
-			for _, i := range task.Input {
-				if i.Validator != nil {
-					err := i.Validator.init(vars, []InputId{i.Id})
-					if err != nil {
-						return fmt.Errorf("invalid expression in input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
-					}
-					hasValidator[i.Id] = true
-				} else {
-					needsToBeUsedInTaskValidator = append(needsToBeUsedInTaskValidator, i.Id)
-				}
+		if i.Id == "" {
+			return i.pos.errorf("no id at input in chapter '%s' task '%s'", c.Title, task.Name)
+		}
 
-				if task.Validator == nil && i.Validator == nil {
-					return fmt.Errorf("validator is missing in input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
-				}
-			}
-			task.inputHasValidator = hasValidator
+		if i.Points < 0 {
+			return i.pos.errorf("negative points at input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
+		}
 
-			if task.Validator != nil {
-				err := task.Validator.init(vars, needsToBeUsedInTaskValidator)
-				if err != nil {
-					return fmt.Errorf("invalid expression in chapter '%s' task '%s': %w", c.Title, task.Name, err)
-				}
-			} else {
-				if len(needsToBeUsedInTaskValidator) > 0 {
-					return fmt.Errorf("validator is missing in chapter '%s' task '%s'", c.Title, task.Name)
-				}
+		if err := checkIdent(string(i.Id)); err != nil {
+			return i.pos.errorf("invalid id '%s' at input in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+
+		if _, ok := vars[i.Id]; ok {
+			return i.pos.errorf("duplicate input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
+		}
+		vars[i.Id] = i.Type
+
+		if i.Label == "" {
+			return i.pos.errorf("no label at input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
+		}
+		if err := task.Param.checkRefs(i.Label, "label"); err != nil {
+			return i.pos.errorf("error at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+
+		if err := i.initTruthTable(); err != nil {
+			return i.pos.errorf("invalid truth table at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+
+		if err := i.initMatrix(); err != nil {
+			return i.pos.errorf("invalid matrix at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+
+		if err := i.initOptions(task.Param); err != nil {
+			return i.pos.errorf("invalid options at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+		if err := i.Hint.init(task.Param); err != nil {
+			return i.pos.errorf("invalid hint at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+	}
+
+	hasValidator := make(map[InputId]bool)
+	var needsToBeUsedInTaskValidator []InputId
+	for _, i := range task.Input {
+		if i.Validator != nil {
+			err := i.Validator.init(vars, []InputId{i.Id}, task.Param, l.functions)
+			if err != nil {
+				return i.Validator.pos.errorf("invalid expression in input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
 			}
-			task.tid = task.createId()
+			err = i.Validator.checkOptions(task.Input)
+			if err != nil {
+				return i.Validator.pos.errorf("invalid test in input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+			}
+			hasValidator[i.Id] = true
+		} else {
+			needsToBeUsedInTaskValidator = append(needsToBeUsedInTaskValidator, i.Id)
+		}
+
+		if task.Validator == nil && i.Validator == nil {
+			return i.pos.errorf("validator is missing in input id '%s' in chapter '%s' task '%s'", i.Id, c.Title, task.Name)
+		}
+	}
+	task.inputHasValidator = hasValidator
+
+	for _, i := range task.Input {
+		if err := i.initFollowUp(task.Input); err != nil {
+			return i.pos.errorf("invalid follow-up at input id '%s' in chapter '%s' task '%s': %w", i.Id, c.Title, task.Name, err)
+		}
+	}
+
+	if task.Validator != nil {
+		err := task.Validator.init(vars, needsToBeUsedInTaskValidator, task.Param, l.functions)
+		if err != nil {
+			return task.Validator.pos.errorf("invalid expression in chapter '%s' task '%s': %w", c.Title, task.Name, err)
+		}
+		if task.Validator.followUp != nil {
+			return task.Validator.pos.errorf("follow-up expressions are only allowed at inputs in chapter '%s' task '%s'", c.Title, task.Name)
+		}
+		err = task.Validator.checkOptions(task.Input)
+		if err != nil {
+			return task.Validator.pos.errorf("invalid test in chapter '%s' task '%s': %w", c.Title, task.Name, err)
+		}
+	} else {
+		if len(needsToBeUsedInTaskValidator) > 0 {
+			return task.pos.errorf("validator is missing in chapter '%s' task '%s'", c.Title, task.Name)
 		}
 	}
+	task.oldIds = task.OldId
+	task.hashId = task.createId()
+	if task.Id != "" {
+		if err := checkIdent(string(task.Id)); err != nil {
+			return task.pos.errorf("invalid id '%s' in chapter '%s' task '%s': %w", task.Id, c.Title, task.Name, err)
+		}
+		task.tid = task.Id
+	} else {
+		task.tid = task.hashId
+	}
 	return nil
 }
 
@@ -540,24 +1068,40 @@ func (c ChapterList) _get(num ChapterNum) *Chapter {
 }
 
 type Lecture struct {
-	Id          LectureId `xml:"id,attr"`
-	Title       string
-	Author      string
-	AuthorEMail string
-	Description string
-	Chapter     ChapterList
-	folder      string
-	files       map[string][]byte
+	Id           LectureId `xml:"id,attr"`
+	Title        string
+	Author       string
+	AuthorEMail  string
+	Description  string
+	Functions    []*Functions
+	TaskTemplate []*TaskTemplate
+	Chapter      ChapterList
+	folder       string
+	files        map[string][]byte
+	migration    map[TaskId]TaskId
+	functions    *functions
+	tags         map[string][]*Task
 }
 
 func (l *Lecture) TaskCount() int {
 	n := 0
-	for _, c := range l.Chapter {
-		n += c.Tasks()
+	for range l.Iter {
+		n++
 	}
 	return n
 }
 
+// HasPoints returns true if points are given in the lecture.
+// If not, there is no need to show the points to the user.
+func (l *Lecture) HasPoints() bool {
+	for task := range l.Iter {
+		if task.hasPoints() {
+			return true
+		}
+	}
+	return false
+}
+
 func (l *Lecture) LID() LectureId {
 	return l.Id
 }
@@ -602,6 +1146,22 @@ func (l *Lecture) Init() error {
 		return err
 	}
 
+	tmpl := templates{}
+	if err := tmpl.add(l.TaskTemplate); err != nil {
+		return fmt.Errorf("error in templates of lecture '%s': %w", l.Title, err)
+	}
+	if err := l.Chapter.collectTemplates(tmpl); err != nil {
+		return fmt.Errorf("error in templates of lecture '%s': %w", l.Title, err)
+	}
+	if err := l.Chapter.expandTemplates(tmpl); err != nil {
+		return fmt.Errorf("error in lecture '%s': %w", l.Title, err)
+	}
+
+	l.functions, err = compileFunctions(l.Chapter.collectFunctions(l.Functions))
+	if err != nil {
+		return fmt.Errorf("error in functions of lecture '%s': %w", l.Title, err)
+	}
+
 	for cnum, chapter := range l.Chapter {
 		err = chapter.init(ChapterNum{cnum}, l)
 		if err != nil {
@@ -609,30 +1169,92 @@ func (l *Lecture) Init() error {
 		}
 	}
 
+	err = l.initRequirements()
+	if err != nil {
+		return fmt.Errorf("error in lecture '%s': %w", l.Title, err)
+	}
+
+	l.initTags()
+
+	err = l.initMigration()
+	if err != nil {
+		return fmt.Errorf("error in lecture '%s': %w", l.Title, err)
+	}
+
 	log.Printf("lecture '%s' (id=%s) initialized with %d tasks and %d images", l.Title, l.Id, l.TaskCount(), len(l.files))
 	return nil
 }
 
+// initMigration checks the task ids for uniqueness and creates the
+// mapping of old task ids to the current ones.
+func (l *Lecture) initMigration() error {
+	tasks := map[TaskId]*Task{}
+	for task := range l.Iter {
+		// tasks without an explicit id and the same content share the id
+		if other, ok := tasks[task.tid]; ok && (task.Id != "" || other.Id != "") {
+			return task.pos.errorf("task '%s' in chapter '%s' has the same id as task '%s' in chapter '%s'",
+				task.Name, task.chapter.Title, other.Name, other.chapter.Title)
+		}
+		tasks[task.tid] = task
+	}
+
+	l.migration = map[TaskId]TaskId{}
+	for task := range l.Iter {
+		for _, old := range task.oldIds {
+			if _, ok := tasks[old]; ok {
+				return task.pos.errorf("old id '%s' of task '%s' in chapter '%s' is used by an existing task", old, task.Name, task.chapter.Title)
+			}
+			if other, ok := l.migration[old]; ok && other != task.tid {
+				return task.pos.errorf("old id '%s' of task '%s' in chapter '%s' is used multiple times", old, task.Name, task.chapter.Title)
+			}
+			l.migration[old] = task.tid
+		}
+	}
+
+	// The content hash was used as id before the explicit id was given.
+	// It is only migrated if it identifies a single task.
+	hashCount := map[TaskId]int{}
+	for task := range l.Iter {
+		hashCount[task.hashId]++
+	}
+	for task := range l.Iter {
+		if task.Id == "" || hashCount[task.hashId] > 1 {
+			continue
+		}
+		if _, ok := l.migration[task.hashId]; !ok {
+			l.migration[task.hashId] = task.tid
+		}
+	}
+	return nil
+}
+
+// MigrateId returns the current id of a task which had the given id in the past.
+func (l *Lecture) MigrateId(old TaskId) (TaskId, bool) {
+	tid, ok := l.migration[old]
+	return tid, ok
+}
+
 func (l *Lecture) resolveIncludes(chapters []*Chapter) error {
 	for i, c := range chapters {
 		if c.Include != "" {
 			if !c.IsEmpty() {
-				return fmt.Errorf("chapter referencing %s contains also other data which is ignored", c.Include)
+				return c.pos.errorf("chapter referencing %s contains also other data which is ignored", c.Include)
 			}
 
 			fi, ok := l.files[c.Include]
 			if !ok {
-				return fmt.Errorf("chapter reference %s not found", c.Include)
+				return c.pos.errorf("chapter reference %s not found", c.Include)
 			}
 
 			var ch Chapter
 			err := xml.Unmarshal(fi, &ch)
 			if err != nil {
-				return err
+				return c.pos.errorf("error parsing file %s: %w", c.Include, err)
 			}
+			ch.setFile(c.Include)
 
 			if ch.Include != "" {
-				return fmt.Errorf("chapter %s contains reference to chapter %s", c.Include, ch.Include)
+				return ch.pos.errorf("chapter %s contains reference to chapter %s", c.Include, ch.Include)
 			}
 
 			chapters[i] = &ch
@@ -747,6 +1369,83 @@ func (l *Lecture) HasTask(tid TaskId) bool {
 	return false
 }
 
+// Markdown returns an iterator over all markdown texts of the lecture.
+// The first value describes where the text is located.
+// The task parameters are substituted using the given seed.
+func (l *Lecture) Markdown(seed string) func(yield func(string, string) bool) {
+	return func(yield func(string, string) bool) {
+		if !yield("lecture description", l.Description) {
+			return
+		}
+		l.Chapter.markdown(seed, yield)
+	}
+}
+
+func (c ChapterList) markdown(seed string, yield func(string, string) bool) bool {
+	for _, ch := range c {
+		if !yield(fmt.Sprintf("description of chapter '%s'", ch.Title), ch.Description) {
+			return false
+		}
+		if !ch.Chapter.markdown(seed, yield) {
+			return false
+		}
+		for _, task := range ch.Task {
+			if !task.markdown(seed, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t *Task) markdown(seed string, yield func(string, string) bool) bool {
+	params, err := t.CreateParams(seed)
+	if err != nil {
+		params = nil
+	}
+	where := fmt.Sprintf("chapter '%s' task '%s'", t.chapter.Title, t.Name)
+	if !yield("question of "+where, params.Substitute(t.Question)) {
+		return false
+	}
+	for _, i := range t.Input {
+		iWhere := fmt.Sprintf("input '%s' of %s", i.Id, where)
+		if !yield("label of "+iWhere, params.Substitute(i.Label)) {
+			return false
+		}
+		for _, o := range i.Option {
+			if !yield(fmt.Sprintf("option '%s' of %s", o.Value, iWhere), params.Substitute(o.Label)) {
+				return false
+			}
+		}
+		if !i.Validator.markdown(params, "validator of "+iWhere, yield) {
+			return false
+		}
+		if !i.Hint.markdown(params, "hint of "+iWhere, yield) {
+			return false
+		}
+	}
+	if !t.Hint.markdown(params, "hint of "+where, yield) {
+		return false
+	}
+	return t.Validator.markdown(params, "validator of "+where, yield)
+}
+
+func (v *Validator) markdown(params Params, where string, yield func(string, string) bool) bool {
+	if v == nil {
+		return true
+	}
+	if !yield("help of "+where, params.Substitute(v.Help)) ||
+		!yield("explanation of "+where, params.Substitute(v.Explanation)) {
+		return false
+	}
+	for _, m := range v.Mistake {
+		if !yield("mistake '"+m.Id+"' of "+where, params.Substitute(m.Message)) {
+			return false
+		}
+	}
+	return true
+}
+
 type Lectures struct {
 	rwMutex  sync.RWMutex
 	lectures map[LectureId]*Lecture
@@ -863,6 +1562,8 @@ func toValue(v interface{}) (value.Value, bool) {
 		return value.Float(v), true
 	case bool:
 		return value.Bool(v), true
+	case [][]string:
+		return matrixToValue(v), true
 	}
 	return nil, false
 }
@@ -882,17 +1583,127 @@ func (d DataMap) Size() int {
 	return len(d)
 }
 
-func (t *Task) Validate(input DataMap, showResult bool) map[InputId]string {
+// Validate validates the given input using the given task parameters.
+func (t *Task) Validate(input DataMap, params Params, showResult bool) map[InputId]string {
+	return t.Score(input, params, showResult).Messages
+}
+
+// Score validates the input and returns the result containing the
+// messages and the score of the task in the range [0,1].
+// The score is the weighted mean of the validator scores. The points
+// of the inputs are used as weights. The task validator is weighted
+// by the inputs which have no validator of their own.
+// A follow-up input counts as correct if it is correct with respect to
+// the wrong answers the user has given to the inputs it depends on.
+func (t *Task) Score(input DataMap, params Params, showResult bool) *Result {
 	m := value.NewMap(input)
-	result := make(map[InputId]string)
-	t.Validator.ToResultMap(m, "_task_", result, showResult)
+	result := newResult()
+	scores := make(map[InputId]float64)
+	mistakes := make(map[InputId]*Mistake)
+	var judgedByTask []*Input
 	for _, i := range t.Input {
-		i.Validator.ToResultMap(m, i.Id, result, showResult)
+		if i.Validator != nil {
+			scores[i.Id], mistakes[i.Id] = i.Validator.ToResultMap(m, params, i.Id, []*Input{i}, result, showResult)
+		} else {
+			judgedByTask = append(judgedByTask, i)
+		}
 	}
+	t.checkFollowUps(m, params, scores, mistakes, result)
 
+	var sum, weights float64
+	taskWeight := 0.0
+	for _, i := range t.Input {
+		if i.Validator == nil {
+			taskWeight += i.weight()
+		} else {
+			if mistake := mistakes[i.Id]; mistake != nil {
+				result.Mistakes = append(result.Mistakes, MistakeKey(i.Id, mistake))
+			}
+			sum += i.weight() * scores[i.Id]
+			weights += i.weight()
+		}
+	}
+	if t.Validator != nil {
+		if taskWeight == 0 {
+			taskWeight = 1
+		}
+		score, mistake := t.Validator.ToResultMap(m, params, "_task_", judgedByTask, result, showResult)
+		if mistake != nil {
+			result.Mistakes = append(result.Mistakes, MistakeKey("_task_", mistake))
+		}
+		sum += taskWeight * score
+		weights += taskWeight
+	}
+
+	if weights == 0 {
+		result.Score = 1
+	} else {
+		result.Score = sum / weights
+	}
+	return result
+}
+
+// MaxPoints returns the points which can be reached in this task.
+// If no points are given at the task, the points of the inputs are added up.
+// If there are no points at all, the task counts one point.
+func (t *Task) MaxPoints() float64 {
+	if t.Points > 0 {
+		return t.Points
+	}
+	p := 0.0
+	for _, i := range t.Input {
+		p += i.Points
+	}
+	if p > 0 {
+		return p
+	}
+	return 1
+}
+
+// AttemptLimit returns the number of failed attempts allowed.
+// If no limit is given at the task, the limit of the chapter is used.
+// Zero means that the number of attempts is not limited.
+func (t *Task) AttemptLimit() int {
+	if t.MaxAttempts > 0 {
+		return t.MaxAttempts
+	}
+	for c := t.chapter; c != nil; c = c.ParentChapter {
+		if c.MaxAttempts > 0 {
+			return c.MaxAttempts
+		}
+	}
+	return 0
+}
+
+// Explanations returns the explanations of all validators.
+// The task validator's explanation is stored with the id "_task_".
+func (t *Task) Explanations(params Params) map[InputId]string {
+	result := make(map[InputId]string)
+	t.Validator.addExplanation(params, "_task_", result)
+	for _, i := range t.Input {
+		i.Validator.addExplanation(params, i.Id, result)
+	}
 	return result
 }
 
+func (v *Validator) addExplanation(params Params, id InputId, result map[InputId]string) {
+	if v != nil && v.Explanation != "" {
+		result[id] = params.Substitute("Lösung:\n\n" + v.Explanation)
+	}
+}
+
+func (t *Task) hasPoints() bool {
+	if t.Points > 0 {
+		return true
+	}
+	for _, i := range t.Input {
+		if i.Points > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Task) createId() TaskId {
 	h := sha1.New()
 	h.Write([]byte(t.Name))
@@ -900,6 +1711,10 @@ func (t *Task) createId() TaskId {
 	for _, i := range t.Input {
 		h.Write([]byte(i.Id))
 		h.Write([]byte(i.Label))
+		for _, o := range i.Option {
+			h.Write([]byte(o.Value))
+			h.Write([]byte(o.Label))
+		}
 	}
 	return TaskId(fmt.Sprintf("%x", h.Sum(nil)))
 }
@@ -909,6 +1724,15 @@ type Expression struct {
 	fu         funcGen.Func[float64]
 }
 
+// eval evaluates the expression with the given arguments.
+func (e Expression) eval(args ...float64) (float64, error) {
+	r, err := e.fu.Eval(args...)
+	if err != nil {
+		return 0, GuiError{message: "Fehler bei der Berechnung von '" + e.expression + "'", cause: err}
+	}
+	return r, nil
+}
+
 func (e Expression) ToList() (*value.List, bool) {
 	return nil, false
 }
@@ -986,9 +1810,9 @@ func createExpressionMethods(parser *parser2.Parser[float64]) value.MethodMap {
 						return nil, fmt.Errorf("expected float, got %v", v)
 					}
 				}
-				r, err := e.fu.Eval(args...)
+				r, err := e.eval(args...)
 				if err != nil {
-					return nil, GuiError{message: "Fehler bei der Berechnung von '" + e.expression + "'", cause: err}
+					return nil, err
 				}
 				return value.Float(r), nil
 			} else {
@@ -1032,6 +1856,10 @@ var myParser = value.New().
 	Modify(func(f *value.FunctionGenerator) {
 		ExpressionTypeId = f.RegisterType()
 		f.RegisterMethods(ExpressionTypeId, createExpressionMethods(floatParser.GetParser()))
+		ComplexTypeId = f.RegisterType()
+		f.RegisterMethods(ComplexTypeId, createComplexMethods())
+		ComplexExpressionTypeId = f.RegisterType()
+		f.RegisterMethods(ComplexExpressionTypeId, createComplexExpressionMethods(complexParser.GetParser()))
 	}).
 	AddStaticFunction("out", funcGen.Function[value.Value]{
 		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
@@ -1114,6 +1942,12 @@ var myParser = value.New().
 			IsPure: true,
 		}.SetDescription("expected", "is", "percent",
 			"compares two values and returns true if the difference is less than the given percent of the expected value"))
+		f.AddStaticFunction("num", funcGen.Function[value.Value]{
+			Func:   value.Must(f.GenerateFromString(`parseFunc(isStr,[]).eval([])`, "isStr")),
+			Args:   1,
+			IsPure: true,
+		}.SetDescription("is",
+			"returns the value entered by the user. This allows to use the user's answer to calculate the expected value of a later input."))
 		f.AddStaticFunction("cmpValuesAbs", funcGen.Function[value.Value]{
 			Func: value.Must(f.GenerateFromString(`let isExp=parseFunc(isStr,[]);
                                                     let is=abs(isExp.eval([]));
@@ -1126,6 +1960,18 @@ var myParser = value.New().
 			IsPure: true,
 		}.SetDescription("expected", "is", "percent",
 			"compares two values and returns true if the difference is less than the given percent of the expected value"))
+		f.AddStaticFunction("cmpQuantity", funcGen.Function[value.Value]{
+			Func:   cmpQuantity,
+			Args:   4,
+			IsPure: true,
+		}.SetDescription("expected", "is", "unit", "percent",
+			"compares a physical quantity with the expected value given in the expected unit. "+
+				"It returns true if the difference is less than the given percent of the expected value and "+
+				"a message if the unit is missing or does not match."))
+		addComplexFunctions(f)
+		addBoolFunctions(f)
+		addRadixFunctions(f)
+		addMatrixFunctions(f)
 
 		p := f.GetParser()
 		//p.SetNumberMatcher(number)
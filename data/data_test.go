@@ -68,7 +68,7 @@ func TestSimple(t *testing.T) {
 	input["linear"] = true
 	input["nlinear"] = true
 
-	result := task1.Validate(input, false)
+	result := task1.Validate(input, nil, false)
 
 	assert.Equal(t, 1, len(result))
 	assert.True(t, strings.Contains(result["_task_"], "nicht richtig"))
@@ -80,7 +80,7 @@ func TestSimple(t *testing.T) {
 	input["linear"] = false
 	input["nlinear"] = true
 
-	result = task1.Validate(input, false)
+	result = task1.Validate(input, nil, false)
 	assert.Equal(t, 0, len(result))
 }
 
@@ -118,17 +118,17 @@ Wie könnte eine gute Näherung für die Gleichung im Durchlassbereich ($U_D>0.5
 
 	input := make(DataMap)
 	input["func1"] = "IS*exp(UD/UT)"
-	result := task2.Validate(input, false)
+	result := task2.Validate(input, nil, false)
 	assert.Equal(t, 0, len(result))
 
 	input = make(DataMap)
 	input["func1"] = "exp(UD/UT)*IS"
-	result = task2.Validate(input, false)
+	result = task2.Validate(input, nil, false)
 	assert.Equal(t, 0, len(result))
 
 	input = make(DataMap)
 	input["func1"] = "3*x^2+1"
-	result = task2.Validate(input, false)
+	result = task2.Validate(input, nil, false)
 	assert.Equal(t, 1, len(result))
 	assert.EqualValues(t, "", result["_task_"])
 }
@@ -184,7 +184,7 @@ func TestValidator(t *testing.T) {
 	for _, tst := range test {
 		t.Run(tst.expr, func(t *testing.T) {
 			val := Validator{Expression: tst.expr}
-			err := val.init(tst.inputs, tst.used)
+			err := val.init(tst.inputs, tst.used, nil, nil)
 			if tst.isValid {
 				assert.NoError(t, err)
 			} else {
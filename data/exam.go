@@ -0,0 +1,52 @@
+package data
+
+import (
+	"time"
+)
+
+// ExamState contains the settings of an exam made by the admin
+type ExamState struct {
+	// Start is the unix time from which on the students can start the exam.
+	// Zero means that the exam is not scheduled.
+	Start int64
+	// Released is set if the students can see their results
+	Released bool
+}
+
+// IsOpen returns true if the exam can be started at the given time
+func (e ExamState) IsOpen(now time.Time) bool {
+	return e.Start > 0 && now.Unix() >= e.Start
+}
+
+// IsExam returns true if the chapter is an exam.
+// A chapter becomes an exam by setting the examMinutes attribute, which is
+// the time each student has to complete the exam.
+func (c *Chapter) IsExam() bool {
+	return c.ExamMinutes > 0
+}
+
+// ExamDuration returns the time each student has to complete the exam
+func (c *Chapter) ExamDuration() time.Duration {
+	return time.Duration(c.ExamMinutes) * time.Minute
+}
+
+// Exams returns all exam chapters of the lecture
+func (l *Lecture) Exams() []*Chapter {
+	var exams []*Chapter
+	for _, c := range l.AllChapters() {
+		if c.IsExam() {
+			exams = append(exams, c)
+		}
+	}
+	return exams
+}
+
+func (c *Chapter) initExam() error {
+	if c.ExamMinutes < 0 {
+		return c.pos.errorf("negative examMinutes in chapter '%s'", c.Title)
+	}
+	if c.IsExam() && c.HasSubChapter() {
+		return c.pos.errorf("exam chapter '%s' must not contain subchapters", c.Title)
+	}
+	return nil
+}
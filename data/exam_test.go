@@ -0,0 +1,62 @@
+package data
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func examLecture(attr string, sub string) string {
+	return fmt.Sprintf(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Übungen</Title>
+        <Chapter %s>
+            <Title>Prüfung</Title>
+            <Task>
+                <Input id="a" type="number">
+                    <Label>a:</Label>
+                    <Validator>
+                        <Expression>cmpValues(1,answer.a,1)</Expression>
+                    </Validator>
+                </Input>
+            </Task>%s
+        </Chapter>
+	</Chapter>
+</Lecture>`, attr, sub)
+}
+
+func TestExam(t *testing.T) {
+	lecture, err := readLectureToTest(examLecture(`examMinutes="30"`, ""))
+	assert.NoError(t, err)
+
+	exams := lecture.Exams()
+	if assert.Equal(t, 1, len(exams)) {
+		exam := exams[0]
+		assert.True(t, exam.IsExam())
+		assert.False(t, lecture.Chapter[0].IsExam())
+		assert.Equal(t, 30*time.Minute, exam.ExamDuration())
+		assert.Equal(t, "0.0", exam.StateId())
+
+		var state LectureState
+		assert.Equal(t, ExamState{}, state.Exam(exam))
+		state.SetExam(exam, ExamState{Start: 100, Released: true})
+		assert.Equal(t, ExamState{Start: 100, Released: true}, state.Exam(exam))
+		assert.True(t, state.Exam(exam).IsOpen(time.Unix(100, 0)))
+		assert.False(t, state.Exam(exam).IsOpen(time.Unix(99, 0)))
+	}
+}
+
+func TestExamInit(t *testing.T) {
+	_, err := readLectureToTest(examLecture(`examMinutes="-1"`, ""))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "negative examMinutes")
+	}
+	_, err = readLectureToTest(examLecture(`examMinutes="10"`, "<Chapter><Title>Sub</Title></Chapter>"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "must not contain subchapters")
+	}
+}
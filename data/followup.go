@@ -0,0 +1,92 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/value"
+	"strings"
+)
+
+// FollowUpMessage is shown if a follow-up input is correct with respect
+// to the wrong answers given by the user before.
+const FollowUpMessage = "Richtig unter Berücksichtigung des vorherigen Ergebnisses!"
+
+// initFollowUp checks the follow-up declaration of the input.
+// A follow-up input depends on other inputs which need to have a validator
+// of their own, so that it is known whether they are answered correctly.
+func (i *Input) initFollowUp(inputs []*Input) error {
+	if i.FollowUp == "" {
+		if i.Validator != nil && i.Validator.followUp != nil {
+			return fmt.Errorf("follow-up expression given, but the input does not declare the inputs it follows up on")
+		}
+		return nil
+	}
+	if i.Validator == nil || i.Validator.followUp == nil {
+		return fmt.Errorf("a follow-up input requires a validator with a follow-up expression")
+	}
+
+	i.dependsOn = nil
+	for _, d := range strings.Split(i.FollowUp, ",") {
+		id := InputId(strings.TrimSpace(d))
+		if id == i.Id {
+			return fmt.Errorf("input '%s' can not follow up on itself", id)
+		}
+		dep := findInput(inputs, id)
+		if dep == nil {
+			return fmt.Errorf("follow-up input '%s' not found", id)
+		}
+		if dep.Validator == nil {
+			return fmt.Errorf("follow-up input '%s' has no validator", id)
+		}
+		if !i.Validator.followUpVar[id] {
+			return fmt.Errorf("'%s' is not used in follow-up expression", id)
+		}
+		i.dependsOn = append(i.dependsOn, id)
+	}
+	if !i.Validator.followUpVar[i.Id] {
+		return fmt.Errorf("'%s' is not used in follow-up expression", i.Id)
+	}
+	return nil
+}
+
+func findInput(inputs []*Input, id InputId) *Input {
+	for _, i := range inputs {
+		if i.Id == id {
+			return i
+		}
+	}
+	return nil
+}
+
+// checkFollowUps checks the follow-up inputs which are not answered
+// correctly. If an input they depend on is also wrong, the follow-up
+// expression is evaluated, which uses the answers given by the user.
+// If it is true, the input counts as correct.
+func (t *Task) checkFollowUps(answer value.Map, params Params, scores map[InputId]float64, mistakes map[InputId]*Mistake, result *Result) {
+	for _, i := range t.Input {
+		if len(i.dependsOn) == 0 || scores[i.Id] >= 1 {
+			continue
+		}
+		dependencyWrong := false
+		for _, d := range i.dependsOn {
+			if scores[d] < 1 {
+				dependencyWrong = true
+				break
+			}
+		}
+		if dependencyWrong && i.Validator.followUpCorrect(answer, params.toMap()) {
+			scores[i.Id] = 1
+			delete(mistakes, i.Id)
+			result.Messages[i.Id] = FollowUpMessage
+			result.accepted[i.Id] = true
+		}
+	}
+}
+
+func (v *Validator) followUpCorrect(answer, param value.Map) bool {
+	r, err := v.followUp.Eval(v.fns.args(answer, param)...)
+	if err != nil {
+		return false
+	}
+	score, ok := resultScore(r)
+	return ok && score >= 1
+}
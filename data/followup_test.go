@@ -0,0 +1,108 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const followUpLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Berechnen Sie den Strom und die Leistung an 10V und 100Ω.</Question>
+            <Input id="I" type="number">
+                <Label>I:</Label>
+                <Validator>
+                    <Expression>cmpValues(0.1,answer.I,1)</Expression>
+                </Validator>
+            </Input>
+            <Input id="P" type="number" followUp="I">
+                <Label>P:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.P,1)</Expression>
+                    <FollowUp>cmpValues(10*num(answer.I),answer.P,1)</FollowUp>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func TestFollowUp(t *testing.T) {
+	lecture, err := readLectureToTest(followUpLecture)
+	assert.NoError(t, err)
+	task := lecture.Chapter[0].Task[0]
+
+	tests := []struct {
+		I, P     string
+		result   map[InputId]string
+		score    float64
+		pCorrect bool
+	}{
+		{"0.1", "1", map[InputId]string{}, 1, true},
+		{"100e-3", "1", map[InputId]string{}, 1, true},
+		{"0.2", "2", map[InputId]string{"I": DefaultMessage, "P": FollowUpMessage}, 0.5, true},
+		{"0.2", "1", map[InputId]string{"I": DefaultMessage}, 0.5, true},
+		{"0.2", "3", map[InputId]string{"I": DefaultMessage, "P": DefaultMessage}, 0, false},
+		{"0.1", "2", map[InputId]string{"P": DefaultMessage}, 0.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.I+","+tt.P, func(t *testing.T) {
+			result := task.Score(DataMap{"I": tt.I, "P": tt.P}, nil, false)
+			assert.Equal(t, tt.result, result.Messages)
+			assert.InDelta(t, tt.score, result.Score, 1e-6)
+			assert.Equal(t, tt.pCorrect, result.Accepted("P"))
+		})
+	}
+}
+
+func TestFollowUpInit(t *testing.T) {
+	tests := []struct {
+		name     string
+		followUp string
+		expr     string
+		err      string
+	}{
+		{"ok", `followUp="I"`, `<FollowUp>cmpValues(10*num(answer.I),answer.P,1)</FollowUp>`, ""},
+		{"noExpression", `followUp="I"`, ``, "requires a validator with a follow-up expression"},
+		{"noAttribute", ``, `<FollowUp>cmpValues(10*num(answer.I),answer.P,1)</FollowUp>`, "does not declare the inputs"},
+		{"self", `followUp="P"`, `<FollowUp>cmpValues(10*num(answer.I),answer.P,1)</FollowUp>`, "can not follow up on itself"},
+		{"unknown", `followUp="U"`, `<FollowUp>cmpValues(10*num(answer.I),answer.P,1)</FollowUp>`, "follow-up input 'U' not found"},
+		{"notUsed", `followUp="I"`, `<FollowUp>cmpValues(1,answer.P,1)</FollowUp>`, "'I' is not used in follow-up expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="I" type="number">
+                <Label>I:</Label>
+                <Validator>
+                    <Expression>cmpValues(0.1,answer.I,1)</Expression>
+                </Validator>
+            </Input>
+            <Input id="P" type="number" ` + tt.followUp + `>
+                <Label>P:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.P,1)</Expression>
+                    ` + tt.expr + `
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -26,21 +26,22 @@ func _mathMlFromAST(a parser2.AST) mathml.Ast {
 		return mathml.SimpleIdent(v.Name)
 	case *parser2.Const[float64]:
 		return mathml.SimpleNumber(fmt.Sprintf("%.6g", v.Value))
+	case *parser2.Const[complex128]:
+		return complexToMathMl(v.Value)
 	case *parser2.Operate:
+		if c, ok := v.B.(*parser2.Const[complex128]); ok && v.Operator == "*" && c.Value == degree {
+			return mergeRow(checkBrace(v.A), mathml.SimpleOperator("°"))
+		}
 		switch v.Operator {
 		case "/":
 			return &mathml.Fraction{Top: _mathMlFromAST(v.A), Bottom: _mathMlFromAST(v.B)}
 		case "^":
 			return &mathml.Index{Base: checkBrace(v.A), Up: _mathMlFromAST(v.B)}
 		default:
-			if aop, ok := v.A.(*parser2.Operate); ok && (aop.Operator == "+" || aop.Operator == "-") && (v.Operator == "+" || v.Operator == "-") {
-				return mergeRow(_mathMlFromAST(v.A), mathml.SimpleOperator(v.Operator), checkBrace(v.B))
-			}
-			if bop, ok := v.B.(*parser2.Operate); ok && (bop.Operator == "+" || bop.Operator == "-") && (v.Operator == "+") {
-				return mergeRow(checkBrace(v.A), mathml.SimpleOperator(v.Operator), _mathMlFromAST(v.B))
-			}
-			return mergeRow(checkBrace(v.A), mathml.SimpleOperator(v.Operator), checkBrace(v.B))
+			return mergeRow(braceIfLower(v.A, v.Operator, false), mathml.SimpleOperator(v.Operator), braceIfLower(v.B, v.Operator, true))
 		}
+	case *parser2.Unary:
+		return mergeRow(mathml.SimpleOperator(v.Operator), checkBrace(v.Value))
 	case *parser2.FunctionCall:
 		if id, ok := v.Func.(*parser2.Ident); ok {
 			if id.Name == "sqrt" {
@@ -55,6 +56,8 @@ func _mathMlFromAST(a parser2.AST) mathml.Ast {
 			list = append(list, _mathMlFromAST(ar))
 		}
 		return mathml.NewRow(mathml.SimpleIdent(v.Func.String()), mathml.SimpleOperator("("), mathml.NewRow(list...), mathml.SimpleOperator(")"))
+	case *parser2.ListLiteral:
+		return matrixToMathMl(v)
 	default:
 		panic(fmt.Errorf("unknown type %T", a))
 	}
@@ -68,6 +71,35 @@ func checkBrace(a parser2.AST) mathml.Ast {
 	}
 }
 
+// precedence returns the binding strength of an operator
+func precedence(op string) int {
+	switch op {
+	case "∠":
+		return 0
+	case "=", "<", ">":
+		return 1
+	case "+", "-":
+		return 2
+	case "*", "/":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// braceIfLower adds braces if the operand is an operation which binds
+// weaker than the given operator. The right operand of a non-commutative
+// operator also needs braces if the precedence is the same.
+func braceIfLower(a parser2.AST, op string, right bool) mathml.Ast {
+	if aop, ok := a.(*parser2.Operate); ok {
+		p := precedence(aop.Operator)
+		if p < precedence(op) || (right && p == precedence(op) && (op == "-" || op == "/" || op == "∠")) {
+			return checkBrace(a)
+		}
+	}
+	return _mathMlFromAST(a)
+}
+
 func mergeRow(list ...mathml.Ast) mathml.Ast {
 	var l []mathml.Ast
 	for _, a := range list {
@@ -82,3 +114,21 @@ func mergeRow(list ...mathml.Ast) mathml.Ast {
 	}
 	return mathml.NewRow(l...)
 }
+
+// matrixToMathMl creates a matrix from a list of lists.
+// A list which does not contain lists is shown as a column vector.
+func matrixToMathMl(l *parser2.ListLiteral) mathml.Ast {
+	var table [][]mathml.Ast
+	for _, item := range l.List {
+		if row, ok := item.(*parser2.ListLiteral); ok {
+			var cells []mathml.Ast
+			for _, c := range row.List {
+				cells = append(cells, _mathMlFromAST(c))
+			}
+			table = append(table, cells)
+		} else {
+			table = append(table, []mathml.Ast{_mathMlFromAST(item)})
+		}
+	}
+	return mathml.NewRow(mathml.SimpleOperator("("), mathml.NewTable(table), mathml.SimpleOperator(")"))
+}
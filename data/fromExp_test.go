@@ -27,7 +27,12 @@ func TestFromExpParser(t *testing.T) {
 		{input: "atan2(y,x)", want: "<mrow><mi>atan2</mi><mo>(</mo><mrow><mi>y</mi><mo>,</mo><mi>x</mi></mrow><mo>)</mo></mrow>"},
 		{input: "sqrt(x)", want: "<msqrt><mi>x</mi></msqrt>"},
 		{input: "a^2", want: "<msup><mi>a</mi><mn>2</mn></msup>"},
+		{input: "a+b*c", want: "<mrow><mi>a</mi><mo>+</mo><mi>b</mi><mo>*</mo><mi>c</mi></mrow>"},
+		{input: "a-(b-c)", want: "<mrow><mi>a</mi><mo>-</mo><mo>(</mo><mrow><mi>b</mi><mo>-</mo><mi>c</mi></mrow><mo>)</mo></mrow>"},
+		{input: "-a", want: "<mrow><mo>-</mo><mi>a</mi></mrow>"},
 		{input: "(a+1)^(i+2)", want: "<msup><mrow><mo>(</mo><mrow><mi>a</mi><mo>+</mo><mn>1</mn></mrow><mo>)</mo></mrow><mrow><mi>i</mi><mo>+</mo><mn>2</mn></mrow></msup>"},
+		{input: "[[1,2],[a,b]]", want: "<mrow><mo>(</mo><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>2</mn></mtd></mtr><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr></mtable><mo>)</mo></mrow>"},
+		{input: "[x,y]", want: "<mrow><mo>(</mo><mtable><mtr><mtd><mi>x</mi></mtd></mtr><mtr><mtd><mi>y</mi></mtd></mtr></mtable><mo>)</mo></mrow>"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
@@ -0,0 +1,140 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hneemann/parser2"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"strings"
+)
+
+// Functions contains functions defined by the lecture author.
+// The functions can be used in all expressions of the lecture.
+// Only function definitions of the form 'func name(a,b) expr;' are allowed.
+type Functions struct {
+	Code string `xml:",chardata"`
+	pos  position
+}
+
+// functions are the compiled functions of a lecture.
+// The functions are passed to the expressions as additional arguments.
+type functions struct {
+	names  []string
+	arity  map[string]int
+	values []value.Value
+}
+
+func (f *functions) argNames(args ...string) []string {
+	if f == nil {
+		return args
+	}
+	return append(args, f.names...)
+}
+
+func (f *functions) args(args ...value.Value) []value.Value {
+	if f == nil {
+		return args
+	}
+	return append(args, f.values...)
+}
+
+// checkCalls checks the number of arguments of all calls to lecture functions
+func (f *functions) checkCalls(ast parser2.AST) error {
+	if f == nil {
+		return nil
+	}
+	var err error
+	ast.Traverse(parser2.VisitorFunc(func(a parser2.AST) bool {
+		if fc, ok := a.(*parser2.FunctionCall); ok && err == nil {
+			if id, ok := fc.Func.(*parser2.Ident); ok {
+				if n, ok := f.arity[id.Name]; ok && n != len(fc.Args) {
+					err = fmt.Errorf("function '%s' requires %d arguments, found %d", id.Name, n, len(fc.Args))
+				}
+			}
+		}
+		return true
+	}))
+	return err
+}
+
+// isBuiltin returns true if the name is already used by a constant or a function
+func isBuiltin(name string) bool {
+	if _, err := myParser.Generate(name); err == nil {
+		return true
+	}
+	_, err := myParser.Generate(name + "()")
+	var notFound parser2.NotFoundError
+	return !(errors.As(err, &notFound) && notFound.NotFound() == name)
+}
+
+// compileFunctions compiles all function blocks.
+// A block is able to use the functions defined in the blocks before.
+func compileFunctions(blocks []*Functions) (*functions, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	f := &functions{arity: map[string]int{}}
+	var code strings.Builder
+	lines := 0
+	for _, b := range blocks {
+		root, err := myParser.GetParser().Parse(b.Code + "\n0")
+		if err != nil {
+			return nil, b.pos.exprError(err)
+		}
+		ast := root
+		for {
+			let, ok := ast.(*parser2.Let)
+			if !ok {
+				break
+			}
+			cl, ok := let.Value.(*parser2.ClosureLiteral)
+			if !ok {
+				return nil, b.pos.errorf("only function definitions are allowed, found '%s'", let.Name)
+			}
+			if let.Name == "answer" || let.Name == "param" || isBuiltin(let.Name) {
+				return nil, b.pos.errorf("the name of function '%s' is already in use", let.Name)
+			}
+			if _, ok := f.arity[let.Name]; ok {
+				return nil, b.pos.errorf("function '%s' is defined twice", let.Name)
+			}
+			f.arity[let.Name] = len(cl.Names)
+			f.names = append(f.names, let.Name)
+			ast = let.Inner
+		}
+		if err := f.checkCalls(root); err != nil {
+			return nil, b.pos.errorf("%w", err)
+		}
+
+		code.WriteString(b.Code)
+		code.WriteString("\n")
+		fu, err := myParser.Generate(code.String() + "[" + strings.Join(f.names, ",") + "]")
+		if err != nil {
+			return nil, b.pos.exprErrorOffset(err, lines)
+		}
+		l, err := fu.Eval()
+		if err != nil {
+			return nil, b.pos.errorf("%w", err)
+		}
+		list, ok := l.ToList()
+		if !ok {
+			return nil, b.pos.errorf("functions could not be created")
+		}
+		f.values, err = list.ToSlice(funcGen.NewEmptyStack[value.Value]())
+		if err != nil {
+			return nil, b.pos.errorf("%w", err)
+		}
+		lines += strings.Count(b.Code, "\n") + 1
+	}
+	return f, nil
+}
+
+// collectFunctions returns all function blocks of the chapters
+func (c ChapterList) collectFunctions(blocks []*Functions) []*Functions {
+	for _, ch := range c {
+		blocks = append(blocks, ch.Functions...)
+		blocks = ch.Chapter.collectFunctions(blocks)
+	}
+	return blocks
+}
@@ -0,0 +1,114 @@
+package data
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func functionsLecture(functions, expression, test string) string {
+	return `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Functions>` + functions + `</Functions>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>` + expression + `</Expression>` + test + `
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+}
+
+func TestFunctions(t *testing.T) {
+	lecture, err := readLectureToTest(functionsLecture(`
+        func sq(x) x*x;
+        func checkSq(x, a) cmpValues(sq(x), a, 1);`,
+		"checkSq(3,answer.a)",
+		`<Test a="9" ok="yes"/><Test a="8" ok="no"/>`))
+	assert.NoError(t, err)
+
+	v := lecture.Chapter[0].Task[0].Input[0].Validator
+	ok, _ := v.Validate(value.NewMap(DataMap{"a": "9"}), Params(nil).toMap())
+	assert.True(t, ok)
+	ok, _ = v.Validate(value.NewMap(DataMap{"a": "10"}), Params(nil).toMap())
+	assert.False(t, ok)
+}
+
+func TestFunctionsInit(t *testing.T) {
+	tests := []struct {
+		name          string
+		functions     string
+		expression    string
+		expectedError string
+	}{
+		{"ok", "func sq(x) x*x;", "cmpValues(sq(3),answer.a,1)", ""},
+		{"builtin", "func cmpValues(x) x;", "cmpValues(3,answer.a,1)", "'cmpValues' is already in use"},
+		{"constant", "func pi(x) x;", "cmpValues(3,answer.a,1)", "already a constant named 'pi'"},
+		{"reserved", "func answer(x) x;", "cmpValues(3,answer.a,1)", "'answer' is already in use"},
+		{"twice", "func sq(x) x*x; func sq(x) x;", "cmpValues(3,answer.a,1)", "'sq' is defined twice"},
+		{"noFunc", "let a=1;", "cmpValues(3,answer.a,1)", "only function definitions are allowed"},
+		{"arity", "func sq(x) x*x;", "cmpValues(sq(3,4),answer.a,1)", "function 'sq' requires 1 arguments, found 2"},
+		{"arityInFunc", "func sq(x) x*x;\nfunc q(x) sq();", "cmpValues(3,answer.a,1)", "function 'sq' requires 1 arguments, found 0"},
+		{"unknown", "func sq(x) x*y;", "cmpValues(3,answer.a,1)", "'y' not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(functionsLecture(tt.functions, tt.expression, ""))
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestFunctionsInInclude(t *testing.T) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	addFile := func(name, content string) {
+		w, err := z.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	addFile("lecture.xml", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Functions>func sq(x) x*x;</Functions>
+    <Chapter file="chapter.inc"/>
+</Lecture>`)
+	addFile("chapter.inc", `<Chapter>
+    <Title>Gleichstromkreise</Title>
+    <Functions>
+        func cube(x) sq(x)*x;
+    </Functions>
+    <Task>
+        <Question>Frage</Question>
+        <Input id="a" type="text">
+            <Label>Wert:</Label>
+            <Validator>
+                <Expression>cmpValues(cube(2),answer.a,1)</Expression>
+                <Test a="8" ok="yes"/>
+            </Validator>
+        </Input>
+    </Task>
+</Chapter>`)
+	assert.NoError(t, z.Close())
+
+	_, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+}
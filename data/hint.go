@@ -0,0 +1,90 @@
+package data
+
+import (
+	"fmt"
+)
+
+// Hint is a hint which is shown to the user after the given
+// number of failed attempts. Further hints can be requested by the user.
+type Hint struct {
+	After int    `xml:"after,attr"`
+	Text  string `xml:",chardata"`
+}
+
+// HintList is an ordered list of hints
+type HintList []*Hint
+
+func (hl HintList) init(params ParamList) error {
+	last := 0
+	for i, h := range hl {
+		h.Text = cleanUpMarkdown(h.Text)
+		if h.Text == "" {
+			return fmt.Errorf("empty hint %d", i+1)
+		}
+		if h.After < last {
+			return fmt.Errorf("hint %d is shown before hint %d", i+1, i)
+		}
+		last = h.After
+		if err := params.checkRefs(h.Text, "hint"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visibleCount returns the number of hints visible after the given number
+// of failed attempts and the number of hints requested by the user.
+// Each requested hint reveals one hint beyond the ones shown automatically.
+func (hl HintList) visibleCount(failed, requested int) int {
+	n := 0
+	for n < len(hl) && hl[n].After <= failed {
+		n++
+	}
+	return min(len(hl), n+requested)
+}
+
+// Visible returns the visible hints
+func (hl HintList) Visible(failed, requested int) []*Hint {
+	return hl[:hl.visibleCount(failed, requested)]
+}
+
+// HasMoreHints returns true if there are hints of the task which are not yet visible.
+func (t *Task) HasMoreHints(failed, requested int) bool {
+	if t.Hint.visibleCount(failed, requested) < len(t.Hint) {
+		return true
+	}
+	for _, i := range t.Input {
+		if i.Hint.visibleCount(failed, requested) < len(i.Hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hints returns the visible hints of the given input.
+// The hints of the task itself are returned using the id "_task_".
+func (t *Task) Hints(id InputId, failed, requested int) []*Hint {
+	if id == "_task_" {
+		return t.Hint.Visible(failed, requested)
+	}
+	for _, i := range t.Input {
+		if i.Id == id {
+			return i.Hint.Visible(failed, requested)
+		}
+	}
+	return nil
+}
+
+// ReduceScore reduces the score by the penalty for the hints requested by the user.
+func (t *Task) ReduceScore(score float64, requested int) float64 {
+	return max(0, score*(1-t.HintPenalty*float64(requested)))
+}
+
+func (hl HintList) markdown(params Params, where string, yield func(string, string) bool) bool {
+	for _, h := range hl {
+		if !yield(where, params.Substitute(h.Text)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const hintLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task hintPenalty="0.25">
+            <Question>Frage</Question>
+            <Hint after="3">Task Hint</Hint>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Hint after="1">Hint 1</Hint>
+                <Hint after="2">Hint 2</Hint>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func hintTexts(hints []*Hint) []string {
+	var s []string
+	for _, h := range hints {
+		s = append(s, h.Text)
+	}
+	return s
+}
+
+func TestHints(t *testing.T) {
+	lecture, err := readLectureToTest(hintLecture)
+	assert.NoError(t, err)
+	task := lecture.Chapter[0].Task[0]
+
+	tests := []struct {
+		failed, requested int
+		input             []string
+		task              []string
+		more              bool
+	}{
+		{0, 0, nil, nil, true},
+		{1, 0, []string{"Hint 1"}, nil, true},
+		{0, 1, []string{"Hint 1"}, []string{"Task Hint"}, true},
+		{1, 1, []string{"Hint 1", "Hint 2"}, []string{"Task Hint"}, false},
+		{2, 0, []string{"Hint 1", "Hint 2"}, nil, true},
+		{2, 1, []string{"Hint 1", "Hint 2"}, []string{"Task Hint"}, false},
+		{3, 0, []string{"Hint 1", "Hint 2"}, []string{"Task Hint"}, false},
+		{0, 5, []string{"Hint 1", "Hint 2"}, []string{"Task Hint"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.input, hintTexts(task.Hints("a", tt.failed, tt.requested)))
+		assert.Equal(t, tt.task, hintTexts(task.Hints("_task_", tt.failed, tt.requested)))
+		assert.Equal(t, tt.more, task.HasMoreHints(tt.failed, tt.requested))
+	}
+
+	assert.Equal(t, 1.0, task.ReduceScore(1, 0))
+	assert.Equal(t, 0.5, task.ReduceScore(1, 2))
+	assert.Equal(t, 0.0, task.ReduceScore(1, 5))
+}
+
+func TestHintsInit(t *testing.T) {
+	_, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Hint after="2">Hint 1</Hint>
+                <Hint after="1">Hint 2</Hint>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "hint 2 is shown before hint 1")
+	}
+}
@@ -132,6 +132,49 @@ func TestInit(t *testing.T) {
             </Input>
         </Task>
 	</Chapter>
+</Lecture>`},
+		{
+			expectedError: "duplicate option value 'a'",
+			xml: `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question></Question>
+            <Input id="val1" type="radio">
+                <Label>Auswahl:</Label>
+                <Option value="a">A</Option>
+                <Option value="a">B</Option>
+                <Validator>
+                    <Expression>answer.val1="a"</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`},
+		{
+			expectedError: "'c' is not an option of 'val1'",
+			xml: `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question></Question>
+            <Input id="val1" type="select">
+                <Label>Auswahl:</Label>
+                <Option value="a">A</Option>
+                <Option value="b">B</Option>
+                <Validator>
+                    <Expression>answer.val1="a"</Expression>
+                    <Test val1="c" ok="no"/>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
 </Lecture>`},
 	}
 
@@ -0,0 +1,561 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hneemann/parser2"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"math"
+	"strings"
+)
+
+// maxMatrixSize is the maximum number of rows and columns of a matrix input
+const maxMatrixSize = 10
+
+const (
+	// matrixCellSep separates the cells of a row in a stored matrix answer
+	matrixCellSep = "\t"
+	// matrixRowSep separates the rows in a stored matrix answer
+	matrixRowSep = "\n"
+)
+
+// IsMatrix returns true if the input is a matrix
+func (i *Input) IsMatrix() bool {
+	return i.Type == Matrix
+}
+
+// MatrixRows returns the row indices of a matrix input
+func (i *Input) MatrixRows() []int {
+	return indices(i.Rows)
+}
+
+// MatrixCols returns the column indices of a matrix input
+func (i *Input) MatrixCols() []int {
+	return indices(i.Cols)
+}
+
+func indices(n int) []int {
+	l := make([]int, n)
+	for i := range l {
+		l[i] = i
+	}
+	return l
+}
+
+// JoinMatrix creates the stored answer of a matrix input from its cells
+func (i *Input) JoinMatrix(cell func(row, col int) string) string {
+	var sb strings.Builder
+	clean := strings.NewReplacer(matrixCellSep, " ", matrixRowSep, " ")
+	for r := range i.Rows {
+		if r > 0 {
+			sb.WriteString(matrixRowSep)
+		}
+		for c := range i.Cols {
+			if c > 0 {
+				sb.WriteString(matrixCellSep)
+			}
+			sb.WriteString(clean.Replace(strings.TrimSpace(cell(r, c))))
+		}
+	}
+	return sb.String()
+}
+
+// SplitMatrix splits a stored answer of a matrix input into its cells.
+// The result always has the size of the matrix input.
+func (i *Input) SplitMatrix(answer string) [][]string {
+	rows := strings.Split(answer, matrixRowSep)
+	m := make([][]string, i.Rows)
+	for r := range m {
+		m[r] = make([]string, i.Cols)
+		if r < len(rows) {
+			copy(m[r], strings.Split(rows[r], matrixCellSep))
+		}
+	}
+	return m
+}
+
+// MatrixCell returns the content of a cell of a matrix answer
+func MatrixCell(answer any, row, col int) string {
+	m, ok := answer.([][]string)
+	if !ok || row >= len(m) || col >= len(m[row]) {
+		return ""
+	}
+	return m[row][col]
+}
+
+// parseMatrixTest parses the matrix given in a test.
+// The rows are separated by semicolons, the cells by commas.
+func parseMatrixTest(s string) [][]string {
+	var m [][]string
+	for _, row := range strings.Split(s, ";") {
+		var cells []string
+		for _, c := range strings.Split(row, ",") {
+			cells = append(cells, strings.TrimSpace(c))
+		}
+		m = append(m, cells)
+	}
+	return m
+}
+
+// matrixToValue converts a matrix answer to a nested list
+func matrixToValue(m [][]string) value.Value {
+	rows := make([]value.Value, len(m))
+	for r, row := range m {
+		cells := make([]value.Value, len(row))
+		for c, cell := range row {
+			cells[c] = value.String(cell)
+		}
+		rows[r] = value.NewList(cells...)
+	}
+	return value.NewList(rows...)
+}
+
+func (i *Input) initMatrix() error {
+	if !i.IsMatrix() {
+		if i.Rows != 0 || i.Cols != 0 {
+			return errors.New("rows and cols are only allowed at matrix inputs")
+		}
+		return nil
+	}
+	if i.Rows < 1 || i.Rows > maxMatrixSize || i.Cols < 1 || i.Cols > maxMatrixSize {
+		return fmt.Errorf("rows and cols need to be in the range [1,%d]", maxMatrixSize)
+	}
+	return nil
+}
+
+// matrix is a matrix of numbers used by the matrix functions
+type matrix [][]float64
+
+func newMatrix(rows, cols int) matrix {
+	m := make(matrix, rows)
+	for r := range m {
+		m[r] = make([]float64, cols)
+	}
+	return m
+}
+
+func (m matrix) rows() int {
+	return len(m)
+}
+
+func (m matrix) cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+func (m matrix) isSquare() bool {
+	return m.rows() == m.cols()
+}
+
+func (m matrix) clone() matrix {
+	c := newMatrix(m.rows(), m.cols())
+	for r := range m {
+		copy(c[r], m[r])
+	}
+	return c
+}
+
+func (m matrix) transpose() matrix {
+	t := newMatrix(m.cols(), m.rows())
+	for r := range m {
+		for c := range m[r] {
+			t[c][r] = m[r][c]
+		}
+	}
+	return t
+}
+
+func (m matrix) mul(o matrix) (matrix, error) {
+	if m.cols() != o.rows() {
+		return nil, fmt.Errorf("can not multiply a %dx%d matrix with a %dx%d matrix", m.rows(), m.cols(), o.rows(), o.cols())
+	}
+	p := newMatrix(m.rows(), o.cols())
+	for r := range p {
+		for c := range p[r] {
+			s := 0.0
+			for k := range o {
+				s += m[r][k] * o[k][c]
+			}
+			p[r][c] = s
+		}
+	}
+	return p, nil
+}
+
+// pivot swaps the row with the largest absolute value in the given column
+// into the given row. The first result is false if the column is zero, the
+// second result is true if rows were swapped.
+func (m matrix) pivot(col int) (bool, bool) {
+	best := col
+	for r := col + 1; r < m.rows(); r++ {
+		if math.Abs(m[r][col]) > math.Abs(m[best][col]) {
+			best = r
+		}
+	}
+	if m[best][col] == 0 {
+		return false, false
+	}
+	if best != col {
+		m[best], m[col] = m[col], m[best]
+		return true, true
+	}
+	return true, false
+}
+
+func (m matrix) det() (float64, error) {
+	if !m.isSquare() {
+		return 0, fmt.Errorf("the determinant requires a square matrix, found %dx%d", m.rows(), m.cols())
+	}
+	a := m.clone()
+	d := 1.0
+	for c := range a {
+		ok, swapped := a.pivot(c)
+		if !ok {
+			return 0, nil
+		}
+		if swapped {
+			d = -d
+		}
+		d *= a[c][c]
+		for r := c + 1; r < a.rows(); r++ {
+			f := a[r][c] / a[c][c]
+			for k := c; k < a.cols(); k++ {
+				a[r][k] -= f * a[c][k]
+			}
+		}
+	}
+	return d, nil
+}
+
+func (m matrix) inv() (matrix, error) {
+	if !m.isSquare() {
+		return nil, fmt.Errorf("the inverse requires a square matrix, found %dx%d", m.rows(), m.cols())
+	}
+	n := m.rows()
+	a := newMatrix(n, 2*n)
+	for r := range m {
+		copy(a[r], m[r])
+		a[r][n+r] = 1
+	}
+	for c := 0; c < n; c++ {
+		if ok, _ := a.pivot(c); !ok {
+			return nil, errors.New("the matrix is singular")
+		}
+		p := a[c][c]
+		for k := range a[c] {
+			a[c][k] /= p
+		}
+		for r := range a {
+			if r != c && a[r][c] != 0 {
+				f := a[r][c]
+				for k := range a[r] {
+					a[r][k] -= f * a[c][k]
+				}
+			}
+		}
+	}
+	res := newMatrix(n, n)
+	for r := range res {
+		copy(res[r], a[r][n:])
+	}
+	return res, nil
+}
+
+// cellEqual compares two numbers using the given tolerance in percent
+func cellEqual(expected, is, percent float64) bool {
+	if expected == 0 {
+		return math.Abs(is) < percent/100
+	}
+	return math.Abs((is-expected)/expected*100) < percent
+}
+
+// equal compares two matrices of the same size. The answer rows and
+// columns are mapped to the expected rows and columns using the given
+// permutations.
+func (m matrix) equal(is matrix, percent float64, rowPerm, colPerm []int) bool {
+	for r := range m {
+		for c := range m[r] {
+			if !cellEqual(m[rowPerm[r]][colPerm[c]], is[r][c], percent) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// findPermutation searches a permutation of the expected rows which matches the
+// rows of the answer. If symmetric is set, the columns are permuted in the same way.
+func (m matrix) findPermutation(is matrix, percent float64, symmetric bool) bool {
+	n := m.rows()
+	perm := make([]int, n)
+	used := make([]bool, n)
+	ident := indices(m.cols())
+
+	// matches checks the already assigned rows
+	matches := func(row int) bool {
+		for c := range is[row] {
+			if symmetric {
+				if c <= row && !cellEqual(m[perm[row]][perm[c]], is[row][c], percent) ||
+					c <= row && !cellEqual(m[perm[c]][perm[row]], is[c][row], percent) {
+					return false
+				}
+			} else if !cellEqual(m[perm[row]][c], is[row][c], percent) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var search func(row int) bool
+	search = func(row int) bool {
+		if row == n {
+			if symmetric {
+				return m.equal(is, percent, perm, perm)
+			}
+			return m.equal(is, percent, perm, ident)
+		}
+		for p := 0; p < n; p++ {
+			if used[p] {
+				continue
+			}
+			perm[row] = p
+			used[p] = true
+			if matches(row) && search(row+1) {
+				return true
+			}
+			used[p] = false
+		}
+		return false
+	}
+	return search(0)
+}
+
+// cmpMatrix compares two matrices using the given tolerance in percent.
+// If permute is set, the rows of the answer may be permuted. For square
+// matrices the columns are permuted in the same way as the rows, as it
+// happens if the nodes of a network are numbered differently.
+func cmpMatrix(expected, is matrix, percent float64, permute bool) value.Value {
+	if expected.rows() != is.rows() || expected.cols() != is.cols() {
+		return value.String(fmt.Sprintf("Es wird eine %dx%d Matrix erwartet!", expected.rows(), expected.cols()))
+	}
+	ident := indices(max(expected.rows(), expected.cols()))
+	if expected.equal(is, percent, ident, ident) {
+		return value.Bool(true)
+	}
+	if permute {
+		return value.Bool(expected.findPermutation(is, percent, expected.isSquare()))
+	}
+	return value.Bool(false)
+}
+
+// toNumber converts a cell of a matrix to a number.
+// Strings are parsed as plain expressions entered by the user, units
+// are not accepted.
+func toNumber(v value.Value) (float64, error) {
+	if s, ok := v.(value.String); ok {
+		if strings.TrimSpace(string(s)) == "" {
+			return 0, GuiError{message: "Die Matrix ist nicht vollständig ausgefüllt!"}
+		}
+		e, err := createExpression(string(s), nil)
+		if err != nil {
+			return 0, err
+		}
+		return e.(Expression).eval()
+	}
+	if f, ok := v.ToFloat(); ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %v", v)
+}
+
+// toMatrix converts a list of lists to a matrix.
+// A list of numbers is converted to a column vector.
+func toMatrix(stack funcGen.Stack[value.Value], v value.Value) (matrix, error) {
+	list, ok := v.(*value.List)
+	if !ok {
+		return nil, fmt.Errorf("expected a matrix, got %v", v)
+	}
+	rows, err := list.ToSlice(stack)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the matrix is empty")
+	}
+	var m matrix
+	for _, row := range rows {
+		var cells []value.Value
+		if rl, ok := row.(*value.List); ok {
+			cells, err = rl.ToSlice(stack)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cells = []value.Value{row}
+		}
+		if len(m) > 0 && len(cells) != m.cols() {
+			return nil, errors.New("the rows of the matrix differ in length")
+		}
+		r := make([]float64, len(cells))
+		for i, c := range cells {
+			r[i], err = toNumber(c)
+			if err != nil {
+				return nil, err
+			}
+		}
+		m = append(m, r)
+	}
+	if m.cols() == 0 {
+		return nil, errors.New("the matrix is empty")
+	}
+	return m, nil
+}
+
+// toValue converts the matrix to a list of lists
+func (m matrix) toValue() value.Value {
+	rows := make([]value.Value, len(m))
+	for r, row := range m {
+		cells := make([]value.Value, len(row))
+		for c, cell := range row {
+			cells[c] = value.Float(cell)
+		}
+		rows[r] = value.NewList(cells...)
+	}
+	return value.NewList(rows...)
+}
+
+// matrixAst creates the ast of a matrix given as a list of lists.
+// Strings are parsed as plain expressions entered by the user, units
+// are not accepted.
+func matrixAst(stack funcGen.Stack[value.Value], v value.Value) (parser2.AST, error) {
+	if list, ok := v.(*value.List); ok {
+		items, err := list.ToSlice(stack)
+		if err != nil {
+			return nil, err
+		}
+		l := &parser2.ListLiteral{}
+		for _, item := range items {
+			a, err := matrixAst(stack, item)
+			if err != nil {
+				return nil, err
+			}
+			l.List = append(l.List, a)
+		}
+		return l, nil
+	}
+	if s, ok := v.(value.String); ok {
+		if strings.TrimSpace(string(s)) == "" {
+			return &parser2.Ident{Name: "?"}, nil
+		}
+		a, err := floatParser.GetParser().Parse(string(s))
+		if err != nil {
+			return nil, GuiError{message: "Fehler im Ausdruck '" + string(s) + "'", cause: err}
+		}
+		return a, nil
+	}
+	if f, ok := v.ToFloat(); ok {
+		return &parser2.Const[float64]{Value: f}, nil
+	}
+	return nil, fmt.Errorf("expected a number, got %v", v)
+}
+
+func matrixFunction(name string, f func(m matrix) (value.Value, error), description string) (string, funcGen.Function[value.Value]) {
+	return name, funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			m, err := toMatrix(stack, stack.Get(0))
+			if err != nil {
+				return nil, err
+			}
+			return f(m)
+		},
+		Args:   1,
+		IsPure: true,
+	}.SetDescription("matrix", description)
+}
+
+func addMatrixFunctions(f *funcGen.FunctionGenerator[value.Value]) {
+	f.AddStaticFunction(matrixFunction("det", func(m matrix) (value.Value, error) {
+		d, err := m.det()
+		if err != nil {
+			return nil, err
+		}
+		return value.Float(d), nil
+	}, "returns the determinant of a square matrix"))
+	f.AddStaticFunction(matrixFunction("inv", func(m matrix) (value.Value, error) {
+		i, err := m.inv()
+		if err != nil {
+			return nil, err
+		}
+		return i.toValue(), nil
+	}, "returns the inverse of a square matrix"))
+	f.AddStaticFunction(matrixFunction("transpose", func(m matrix) (value.Value, error) {
+		return m.transpose().toValue(), nil
+	}, "returns the transposed matrix"))
+	f.AddStaticFunction("mul", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			a, err := toMatrix(stack, stack.Get(0))
+			if err != nil {
+				return nil, err
+			}
+			b, err := toMatrix(stack, stack.Get(1))
+			if err != nil {
+				return nil, err
+			}
+			p, err := a.mul(b)
+			if err != nil {
+				return nil, err
+			}
+			return p.toValue(), nil
+		},
+		Args:   2,
+		IsPure: true,
+	}.SetDescription("a", "b", "multiplies two matrices. A list of numbers is used as a column vector"))
+	f.AddStaticFunction("cmpMatrix", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, err := toMatrix(stack, stack.Get(0))
+			if err != nil {
+				return nil, fmt.Errorf("error in expected matrix: %w", err)
+			}
+			is, err := toMatrix(stack, stack.Get(1))
+			if err != nil {
+				return nil, err
+			}
+			percent, ok := stack.Get(2).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(2))
+			}
+			permute, ok := stack.Get(3).ToBool()
+			if !ok {
+				return nil, fmt.Errorf("expected a bool, got %v", stack.Get(3))
+			}
+			return cmpMatrix(expected, is, percent, permute), nil
+		},
+		Args:   4,
+		IsPure: true,
+	}.SetDescription("expected", "is", "percent", "permute",
+		"compares two matrices. Each element has to match within the given percentage of the expected value. "+
+			"If permute is true, the rows may be permuted. In a square matrix, the columns have to be "+
+			"permuted in the same way as the rows, as it happens if the nodes of a network are numbered differently"))
+	f.AddStaticFunction("matrixMathMl", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			a, err := matrixAst(stack, stack.Get(0))
+			if err != nil {
+				return nil, err
+			}
+			ml, err := MathMlFromAST(a)
+			if err != nil {
+				return nil, err
+			}
+			sb := strings.Builder{}
+			sb.WriteString("<math xmlns='http://www.w3.org/1998/Math/MathML'>")
+			ml.ToMathMl(&sb, nil)
+			sb.WriteString("</math>")
+			return value.String(sb.String()), nil
+		},
+		Args:   1,
+		IsPure: true,
+	}.SetDescription("matrix", "creates MathML of a matrix given as a list of lists"))
+}
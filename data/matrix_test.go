@@ -0,0 +1,108 @@
+package data
+
+import (
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatrixDet(t *testing.T) {
+	tests := []struct {
+		m    matrix
+		want float64
+	}{
+		{matrix{{2}}, 2},
+		{matrix{{1, 2}, {3, 4}}, -2},
+		{matrix{{0, 1}, {1, 0}}, -1},
+		{matrix{{1, 2}, {2, 4}}, 0},
+		{matrix{{2, 0, 1}, {1, 3, 2}, {1, 1, 2}}, 6},
+	}
+	for _, tt := range tests {
+		d, err := tt.m.det()
+		assert.NoError(t, err)
+		assert.InDelta(t, tt.want, d, 1e-9)
+	}
+
+	_, err := matrix{{1, 2}}.det()
+	assert.Error(t, err)
+}
+
+func TestMatrixInv(t *testing.T) {
+	m := matrix{{0, 1, 2}, {1, 0, 3}, {4, -3, 8}}
+	i, err := m.inv()
+	assert.NoError(t, err)
+	p, err := m.mul(i)
+	assert.NoError(t, err)
+	for r := range p {
+		for c := range p[r] {
+			want := 0.0
+			if r == c {
+				want = 1
+			}
+			assert.InDelta(t, want, p[r][c], 1e-9)
+		}
+	}
+
+	_, err = matrix{{1, 2}, {2, 4}}.inv()
+	assert.Error(t, err)
+}
+
+func TestSplitMatrix(t *testing.T) {
+	i := &Input{Type: Matrix, Rows: 2, Cols: 2}
+	cells := [][]string{{"1", " 2 "}, {"a\tb", ""}}
+	s := i.JoinMatrix(func(r, c int) string { return cells[r][c] })
+	assert.Equal(t, "1\t2\na b\t", s)
+	assert.Equal(t, [][]string{{"1", "2"}, {"a b", ""}}, i.SplitMatrix(s))
+	assert.Equal(t, [][]string{{"1", ""}, {"", ""}}, i.SplitMatrix("1"))
+}
+
+func TestMatrixFunctions(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result value.Value
+	}{
+		{`det([[1,2],[3,4]])`, value.Float(-2)},
+		{`det([["1/2","1"],["0","4"]])`, value.Float(2)},
+		{`cmpMatrix(inv([[2,0],[0,4]]),[[0.5,0],[0,0.25]],0.001,false)`, value.Bool(true)},
+		{`cmpMatrix(transpose([[1,2,3]]),[1,2,3],0.001,false)`, value.Bool(true)},
+		{`cmpMatrix(mul([[1,2],[3,4]],[1,1]),[3,7],0.001,false)`, value.Bool(true)},
+		{`cmpMatrix([[1,2],[3,4]],[["1","2"],["3","4.01"]],1,false)`, value.Bool(true)},
+		{`cmpMatrix([[1,2],[3,4]],[["1","2"],["3","4.1"]],1,false)`, value.Bool(false)},
+		{`cmpMatrix([[1,0],[3,4]],[["1","0.001"],["3","4"]],1,false)`, value.Bool(true)},
+		{`cmpMatrix([[1,2],[3,4]],[["1","2","3"]],1,false)`, value.String("Es wird eine 2x2 Matrix erwartet!")},
+		// rows and columns swapped simultaneously
+		{`cmpMatrix([[1,2],[3,4]],[[4,3],[2,1]],1,false)`, value.Bool(false)},
+		{`cmpMatrix([[1,2],[3,4]],[[4,3],[2,1]],1,true)`, value.Bool(true)},
+		{`cmpMatrix([[1,2],[3,4]],[[3,4],[1,2]],1,true)`, value.Bool(false)},
+		{`cmpMatrix([[1,2,3],[4,5,6],[7,8,9]],[[9,7,8],[3,1,2],[6,4,5]],1,true)`, value.Bool(true)},
+		// only rows are swapped in a non-square matrix
+		{`cmpMatrix([[1,2,3],[4,5,6]],[[4,5,6],[1,2,3]],1,true)`, value.Bool(true)},
+		{`matrixMathMl([["1","x"],["",2]])`, value.String("<math xmlns='http://www.w3.org/1998/Math/MathML'><mrow><mo>(</mo><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mi>x</mi></mtd></mtr><mtr><mtd><mi>?</mi></mtd><mtd><mn>2</mn></mtd></mtr></mtable><mo>)</mo></mrow></math>")},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.expr, func(t *testing.T) {
+			f, err := myParser.Generate(tst.expr)
+			assert.NoError(t, err)
+			if f != nil {
+				r, err := f.Eval()
+				assert.NoError(t, err)
+				assert.Equal(t, tst.result, r)
+			}
+		})
+	}
+}
+
+func TestMatrixIncomplete(t *testing.T) {
+	_, err := myParser.Generate(`cmpMatrix([[1,2],[3,4]],[["1","2"],["3",""]],1,false)`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Die Matrix ist nicht vollständig ausgefüllt!")
+}
+
+func TestMatrixUnit(t *testing.T) {
+	f, err := myParser.Generate(`cmpMatrix([[0.005]],[["5 m"]],1,false)`)
+	if err == nil {
+		_, err = f.Eval()
+	}
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"strconv"
+	"strings"
+)
+
+// Mistake describes a common mistake. If the validator fails and the
+// condition is true, the message is shown instead of the default message.
+type Mistake struct {
+	Id        string `xml:"id,attr"`
+	Condition source
+	Message   string
+	fu        funcGen.Func[value.Value]
+	pos       position
+}
+
+// MistakeList is a list of mistakes which are checked in order
+type MistakeList []*Mistake
+
+func (ml MistakeList) init(varsAvail map[InputId]InputType, params ParamList, fns *functions) error {
+	ids := map[string]bool{}
+	for n, m := range ml {
+		if m.Id == "" {
+			m.Id = strconv.Itoa(n + 1)
+		} else if err := checkIdent(m.Id); err != nil {
+			return m.pos.errorf("invalid mistake id '%s': %w", m.Id, err)
+		}
+		if ids[m.Id] {
+			return m.pos.errorf("mistake id '%s' is used twice", m.Id)
+		}
+		ids[m.Id] = true
+
+		m.Message = cleanUpMarkdown(m.Message)
+		if m.Message == "" {
+			return m.pos.errorf("no message in mistake '%s'", m.Id)
+		}
+		if err := params.checkRefs(m.Message, "mistake message"); err != nil {
+			return m.pos.errorf("error in mistake '%s': %w", m.Id, err)
+		}
+
+		if strings.TrimSpace(m.Condition.text) == "" {
+			return m.pos.errorf("no condition in mistake '%s'", m.Id)
+		}
+		f, _, err := m.Condition.compile(fmt.Sprintf("mistake '%s'", m.Id), varsAvail, params, fns)
+		if err != nil {
+			return err
+		}
+		m.fu = f
+	}
+	return nil
+}
+
+// find returns the first mistake whose condition is true.
+// Conditions which cannot be evaluated are treated as false, because
+// the answer may be anything the user entered.
+func (ml MistakeList) find(fns *functions, answer, param value.Value) *Mistake {
+	for _, m := range ml {
+		r, err := m.fu.Eval(fns.args(answer, param)...)
+		if err != nil {
+			continue
+		}
+		if b, ok := r.(value.Bool); ok && bool(b) {
+			return m
+		}
+	}
+	return nil
+}
+
+func (ml MistakeList) get(id string) *Mistake {
+	for _, m := range ml {
+		if m.Id == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// MistakeKey returns the key used to count the given mistake of the
+// validator of the given input.
+func MistakeKey(id InputId, m *Mistake) string {
+	if id == "_task_" {
+		return m.Id
+	}
+	return fmt.Sprintf("%s/%s", id, m.Id)
+}
+
+// MistakeKeys returns the keys of all mistakes defined in the task
+func (t *Task) MistakeKeys() []string {
+	var keys []string
+	for _, i := range t.Input {
+		if i.Validator != nil {
+			for _, m := range i.Validator.Mistake {
+				keys = append(keys, MistakeKey(i.Id, m))
+			}
+		}
+	}
+	if t.Validator != nil {
+		for _, m := range t.Validator.Mistake {
+			keys = append(keys, MistakeKey("_task_", m))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const mistakeLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(10,answer.a,1)</Expression>
+                    <Mistake id="sign">
+                        <Condition>cmpValues(-10,answer.a,1)</Condition>
+                        <Message>Das Vorzeichen ist falsch!</Message>
+                    </Mistake>
+                    <Mistake>
+                        <Condition>cmpValues(1,answer.a,1)</Condition>
+                        <Message>Der Faktor zehn fehlt!</Message>
+                    </Mistake>
+                    <Test a="10" mistake=""/>
+                    <Test a="-10" mistake="sign"/>
+                    <Test a="1" mistake="2" ok="no"/>
+                    <Test a="5" mistake=""/>
+                </Validator>
+            </Input>
+            <Input id="b" type="number">
+                <Label>b:</Label>
+            </Input>
+            <Validator>
+                <Expression>cmpValues(2,answer.b,1)</Expression>
+                <Mistake id="half">
+                    <Condition>cmpValues(1,answer.b,1)</Condition>
+                    <Message>Nur die Hälfte!</Message>
+                </Mistake>
+            </Validator>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func TestMistakes(t *testing.T) {
+	lecture, err := readLectureToTest(mistakeLecture)
+	assert.NoError(t, err)
+	task := lecture.Chapter[0].Task[0]
+
+	assert.Equal(t, []string{"a/sign", "a/2", "half"}, task.MistakeKeys())
+
+	tests := []struct {
+		a, b     string
+		result   map[InputId]string
+		mistakes []string
+	}{
+		{"10", "2", map[InputId]string{}, nil},
+		{"-10", "2", map[InputId]string{"a": "Das Vorzeichen ist falsch!"}, []string{"a/sign"}},
+		{"1", "1", map[InputId]string{"a": "Der Faktor zehn fehlt!", "_task_": "Nur die Hälfte!"}, []string{"a/2", "half"}},
+		{"5", "3", map[InputId]string{"a": DefaultMessage, "_task_": DefaultMessage}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+","+tt.b, func(t *testing.T) {
+			result := task.Score(DataMap{"a": tt.a, "b": tt.b}, nil, false)
+			assert.Equal(t, tt.result, result.Messages)
+			assert.Equal(t, tt.mistakes, result.Mistakes)
+		})
+	}
+}
+
+func TestMistakesInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		mistake string
+		test    string
+		err     string
+	}{
+		{"ok", `<Mistake id="sign"><Condition>cmpValues(-1,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>`, `<Test a="-1" mistake="sign"/>`, ""},
+		{"wrongMistake", `<Mistake id="sign"><Condition>cmpValues(-1,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>`, `<Test a="2" mistake="sign"/>`, "expected mistake 'sign', got none"},
+		{"correct", `<Mistake id="sign"><Condition>cmpValues(-1,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>`, `<Test a="1" mistake="sign"/>`, "but the answer is correct"},
+		{"undefined", `<Mistake id="sign"><Condition>cmpValues(-1,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>`, `<Test a="2" mistake="zero"/>`, "mistake 'zero' is not defined"},
+		{"noMessage", `<Mistake><Condition>cmpValues(-1,answer.a,1)</Condition></Mistake>`, "", "no message in mistake '1'"},
+		{"noCondition", `<Mistake><Message>Vorzeichen</Message></Mistake>`, "", "no condition in mistake '1'"},
+		{"unknownVar", `<Mistake><Condition>cmpValues(-1,answer.b,1)</Condition><Message>Vorzeichen</Message></Mistake>`, "", "'b' is used in mistake '1' but not available"},
+		{"twice", `<Mistake id="s"><Condition>cmpValues(-1,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>
+                   <Mistake id="s"><Condition>cmpValues(-2,answer.a,1)</Condition><Message>Vorzeichen</Message></Mistake>`, "", "mistake id 's' is used twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                    ` + tt.mistake + `
+                    ` + tt.test + `
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	xml := `<Lecture id="EL1">
+    <Title>Elektronik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Die Diode</Title>
+        <Task>
+            <Question>Eine Diode ...</Question>
+            <Input id="pins" type="radio" shuffle="true">
+                <Label>hat</Label>
+                <Option value="one">einen Anschluss</Option>
+                <Option value="two">zwei Anschlüsse</Option>
+                <Option value="three">drei Anschlüsse</Option>
+                <Validator>
+                    <Expression>answer.pins="two"</Expression>
+                    <Test pins="two" ok="yes"/>
+                    <Test pins="three" ok="no"/>
+                </Validator>
+            </Input>
+            <Input id="kind" type="select">
+                <Label>ist</Label>
+                <Option value="lin">linear</Option>
+                <Option value="nlin">nicht linear</Option>
+                <Validator>
+                    <Expression>answer.kind="nlin"</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>
+`
+	lecture, err := readLectureToTest(xml)
+	assert.NoError(t, err)
+
+	task := lecture.Chapter[0].Task[0]
+
+	result := task.Validate(DataMap{"pins": "two", "kind": "nlin"}, nil, false)
+	assert.Equal(t, 0, len(result))
+
+	result = task.Validate(DataMap{"pins": "one", "kind": ""}, nil, false)
+	assert.Equal(t, 2, len(result))
+
+	pins := task.Input[0]
+	assert.Equal(t, pins.Options("a"), pins.Options("a"))
+	assert.ElementsMatch(t, pins.Option, pins.Options("a"))
+	kind := task.Input[1]
+	assert.Equal(t, kind.Option, kind.Options("a"))
+}
@@ -0,0 +1,234 @@
+package data
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Param is a task parameter.
+// If an expression is given, the parameter is derived from the
+// parameters defined before. Otherwise, the value is chosen randomly
+// in the range [Min,Max] using the given step width.
+// If the step width is zero, the value is continuous.
+type Param struct {
+	Name       string  `xml:"name,attr"`
+	Min        float64 `xml:"min,attr"`
+	Max        float64 `xml:"max,attr"`
+	Step       float64 `xml:"step,attr"`
+	Expression string  `xml:",chardata"`
+	fu         funcGen.Func[value.Value]
+	pos        position
+}
+
+func (p *Param) isDerived() bool {
+	return p.fu != nil
+}
+
+func (p *Param) init(defined map[string]bool) error {
+	if p.Name == "" {
+		return fmt.Errorf("parameter without a name")
+	}
+	if err := checkIdent(p.Name); err != nil {
+		return fmt.Errorf("invalid parameter name '%s': %w", p.Name, err)
+	}
+	if defined[p.Name] {
+		return fmt.Errorf("duplicate parameter '%s'", p.Name)
+	}
+
+	p.Expression = strings.TrimSpace(p.Expression)
+	if p.Expression == "" {
+		if p.Max < p.Min {
+			return fmt.Errorf("max is less than min in parameter '%s'", p.Name)
+		}
+		if p.Step < 0 {
+			return fmt.Errorf("negative step in parameter '%s'", p.Name)
+		}
+	} else {
+		f, err := myParser.Generate(p.Expression, "param")
+		if err != nil {
+			return fmt.Errorf("invalid expression in parameter '%s': %w", p.Name, p.pos.exprError(err))
+		}
+		a, err := myParser.GetParser().Parse(p.Expression)
+		if err != nil {
+			return fmt.Errorf("invalid expression in parameter '%s': %w", p.Name, p.pos.exprError(err))
+		}
+		varsUsed := newCollectVars()
+		a.Traverse(varsUsed)
+		for pu := range varsUsed.param {
+			if !defined[pu] {
+				return fmt.Errorf("parameter '%s' uses '%s' which is not defined before", p.Name, pu)
+			}
+		}
+		p.fu = f
+	}
+	defined[p.Name] = true
+	return nil
+}
+
+func (p *Param) random(r *rand.Rand) float64 {
+	if p.Step > 0 {
+		n := int(math.Floor((p.Max-p.Min)/p.Step + 1e-9))
+		return p.Min + float64(r.Intn(n+1))*p.Step
+	}
+	return p.Min + r.Float64()*(p.Max-p.Min)
+}
+
+// ParamList is the list of parameters of a task
+type ParamList []*Param
+
+func (pl ParamList) init() error {
+	defined := map[string]bool{}
+	for _, p := range pl {
+		err := p.init(defined)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pl ParamList) get(name string) *Param {
+	for _, p := range pl {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// create creates the parameter values using the given seed.
+// The fixed map allows to set random parameters to fixed values.
+func (pl ParamList) create(seed string, fixed map[string]float64) (Params, error) {
+	for name := range fixed {
+		p := pl.get(name)
+		if p == nil {
+			return nil, fmt.Errorf("unknown parameter '%s'", name)
+		}
+		if p.isDerived() {
+			return nil, fmt.Errorf("parameter '%s' is derived and can not be set", name)
+		}
+	}
+
+	r := newRand(seed)
+	params := Params{}
+	for _, p := range pl {
+		if p.isDerived() {
+			v, err := p.fu.Eval(value.NewMap(value.RealMap(params)))
+			if err != nil {
+				return nil, fmt.Errorf("error evaluating parameter '%s': %w", p.Name, err)
+			}
+			params[p.Name] = v
+		} else {
+			// the random number is always drawn to keep the
+			// following parameters independent of fixed values
+			v := p.random(r)
+			if f, ok := fixed[p.Name]; ok {
+				v = f
+			}
+			params[p.Name] = value.Float(v)
+		}
+	}
+	return params, nil
+}
+
+// newRand creates a random number generator which
+// always returns the same sequence for the same seed.
+func newRand(seed string) *rand.Rand {
+	h := sha1.Sum([]byte(seed))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(h[:8]))))
+}
+
+const (
+	paramStart = "{{param."
+	paramEnd   = "}}"
+)
+
+// paramRefs returns all parameter names referenced in the given markdown
+func paramRefs(md string) []string {
+	var refs []string
+	for {
+		s := strings.Index(md, paramStart)
+		if s < 0 {
+			return refs
+		}
+		md = md[s+len(paramStart):]
+		e := strings.Index(md, paramEnd)
+		if e < 0 {
+			return refs
+		}
+		refs = append(refs, md[:e])
+		md = md[e+len(paramEnd):]
+	}
+}
+
+// checkRefs checks if all parameters referenced in the markdown are defined
+func (pl ParamList) checkRefs(md string, where string) error {
+	for _, r := range paramRefs(md) {
+		if pl.get(r) == nil {
+			return fmt.Errorf("unknown parameter '%s' in %s", r, where)
+		}
+	}
+	return nil
+}
+
+// Params contains the parameter values of a task for a specific student.
+type Params map[string]value.Value
+
+func (p Params) toMap() value.Map {
+	if p == nil {
+		return value.NewMap(value.RealMap{})
+	}
+	return value.NewMap(value.RealMap(p))
+}
+
+// Substitute replaces all parameter references of the form
+// {{param.name}} by the parameters value.
+func (p Params) Substitute(md string) string {
+	if len(p) == 0 || !strings.Contains(md, paramStart) {
+		return md
+	}
+
+	var sb strings.Builder
+	for {
+		s := strings.Index(md, paramStart)
+		if s < 0 {
+			break
+		}
+		e := strings.Index(md[s:], paramEnd)
+		if e < 0 {
+			break
+		}
+		name := md[s+len(paramStart) : s+e]
+		sb.WriteString(md[:s])
+		if v, ok := p[name]; ok {
+			sb.WriteString(formatParam(v))
+		} else {
+			sb.WriteString(md[s : s+e+len(paramEnd)])
+		}
+		md = md[s+e+len(paramEnd):]
+	}
+	sb.WriteString(md)
+	return sb.String()
+}
+
+func formatParam(v value.Value) string {
+	switch v := v.(type) {
+	case value.Float:
+		return strconv.FormatFloat(float64(v), 'g', 6, 64)
+	case value.String:
+		return string(v)
+	default:
+		s, err := v.ToString(funcGen.NewEmptyStack[value.Value]())
+		if err != nil {
+			return err.Error()
+		}
+		return s
+	}
+}
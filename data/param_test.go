@@ -0,0 +1,108 @@
+package data
+
+import (
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const paramLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Es gilt $R_1={{param.R1}}\u{\Omega}$ und $R_2={{param.R2}}\u{\Omega}$.</Question>
+            <Param name="R1" min="100" max="1000" step="10"/>
+            <Param name="R2" min="100" max="1000" step="10"/>
+            <Param name="Rg">param.R1+param.R2</Param>
+            <Input id="val1" type="text">
+                <Label>$R_g/\u{\Omega}$:</Label>
+                <Validator>
+                    <Expression>cmpValues(param.Rg,answer.val1,1)</Expression>
+                    <Explanation>Der Wert beträgt $R_g={{param.Rg}}\u{\Omega}$.</Explanation>
+                    <Test val1="300" param.R1="100" param.R2="200" ok="yes"/>
+                    <Test val1="400" param.R1="100" param.R2="200" ok="no"/>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func TestParams(t *testing.T) {
+	lecture, err := readLectureToTest(paramLecture)
+	assert.NoError(t, err)
+
+	task := lecture.Chapter[0].Task[0]
+
+	p1, err := task.CreateParams("student1")
+	assert.NoError(t, err)
+	p1b, err := task.CreateParams("student1")
+	assert.NoError(t, err)
+	assert.Equal(t, p1, p1b)
+
+	r1 := float64(p1["R1"].(value.Float))
+	r2 := float64(p1["R2"].(value.Float))
+	assert.True(t, r1 >= 100 && r1 <= 1000)
+	assert.EqualValues(t, r1+r2, p1["Rg"])
+
+	q := p1.Substitute(task.Question)
+	assert.False(t, strings.Contains(q, "{{param."))
+	assert.True(t, strings.Contains(q, "R_1="+formatParam(p1["R1"])))
+
+	result := task.Validate(DataMap{"val1": formatParam(p1["Rg"])}, p1, false)
+	assert.Equal(t, 0, len(result))
+
+	result = task.Validate(DataMap{"val1": "1"}, p1, true)
+	assert.Equal(t, 1, len(result))
+	assert.True(t, strings.Contains(result["val1"], "R_g="+formatParam(p1["Rg"])))
+}
+
+func TestParamsInit(t *testing.T) {
+	tests := []struct {
+		name          string
+		param         string
+		expression    string
+		test          string
+		expectedError string
+	}{
+		{"ok", `<Param name="a" min="1" max="2"/>`, "cmpValues(param.a,answer.val1,1)", "", ""},
+		{"undefined", `<Param name="a" min="1" max="2"/>`, "cmpValues(param.b,answer.val1,1)", "", "parameter 'b' is used but not defined"},
+		{"duplicate", `<Param name="a" min="1" max="2"/><Param name="a" min="1" max="2"/>`, "cmpValues(param.a,answer.val1,1)", "", "duplicate parameter 'a'"},
+		{"minMax", `<Param name="a" min="2" max="1"/>`, "cmpValues(param.a,answer.val1,1)", "", "max is less than min"},
+		{"order", `<Param name="b">param.a*2</Param><Param name="a" min="1" max="2"/>`, "cmpValues(param.b,answer.val1,1)", "", "not defined before"},
+		{"testUnknown", `<Param name="a" min="1" max="2"/>`, "cmpValues(param.a,answer.val1,1)", `<Test val1="1" param.c="1" ok="yes"/>`, "unknown parameter 'c'"},
+		{"testDerived", `<Param name="a" min="1" max="2"/><Param name="b">param.a*2</Param>`, "cmpValues(param.b,answer.val1,1)", `<Test val1="1" param.b="1" ok="yes"/>`, "is derived"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xml := `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>` + tt.param + `
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>` + tt.expression + `</Expression>` + tt.test + `
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+			_, err := readLectureToTest(xml)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.expectedError)
+				}
+			}
+		})
+	}
+}
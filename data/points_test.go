@@ -0,0 +1,106 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const pointsLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task points="6">
+            <Question>Frage</Question>
+            <Input id="a" type="text" points="2">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+            <Input id="b" type="text">
+                <Label>b:</Label>
+                <Validator>
+                    <Expression>if cmpValues(2,answer.b,1) then 1 else if cmpValues(-2,answer.b,1) then 0.5 else 0</Expression>
+                    <Test b="2" score="1"/>
+                    <Test b="-2" score="0.5"/>
+                    <Test b="-2" ok="no"/>
+                    <Test b="3" score="0"/>
+                </Validator>
+            </Input>
+        </Task>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="c" type="text">
+                <Label>c:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.c,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func TestPoints(t *testing.T) {
+	lecture, err := readLectureToTest(pointsLecture)
+	assert.NoError(t, err)
+
+	assert.True(t, lecture.HasPoints())
+	assert.Equal(t, 7.0, lecture.MaxPoints(Selection{}))
+	assert.Equal(t, 7.0, lecture.Chapter[0].MaxPoints(Selection{}))
+
+	task := lecture.Chapter[0].Task[0]
+	assert.Equal(t, 6.0, task.MaxPoints())
+	assert.Equal(t, 1.0, lecture.Chapter[0].Task[1].MaxPoints())
+
+	tests := []struct {
+		a, b  string
+		score float64
+	}{
+		{"1", "2", 1},
+		{"1", "-2", 5.0 / 6},
+		{"1", "3", 2.0 / 3},
+		{"0", "2", 1.0 / 3},
+		{"0", "-2", 1.0 / 6},
+		{"0", "0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+","+tt.b, func(t *testing.T) {
+			result := task.Score(DataMap{"a": tt.a, "b": tt.b}, nil, false)
+			assert.InDelta(t, tt.score, result.Score, 1e-9)
+			assert.Equal(t, tt.score == 1, len(result.Messages) == 0)
+		})
+	}
+
+	result := task.Score(DataMap{"a": "1", "b": "-2"}, nil, false)
+	assert.Equal(t, "Das ist teilweise richtig (50%)!", result.Messages["b"])
+}
+
+func TestPointsInit(t *testing.T) {
+	noPoints, err := readLectureToTest(paramLecture)
+	assert.NoError(t, err)
+	assert.False(t, noPoints.HasPoints())
+
+	_, err = readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="b" type="text">
+                <Label>b:</Label>
+                <Validator>
+                    <Expression>if cmpValues(2,answer.b,1) then 1 else 0.5</Expression>
+                    <Test b="3" score="0"/>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "expected score 0, got 0.5")
+	}
+}
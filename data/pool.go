@@ -0,0 +1,153 @@
+package data
+
+import (
+	"strings"
+	"sync"
+)
+
+// Pool contains tasks from which a fixed number of tasks is drawn
+// randomly for each user.
+// The drawn tasks depend on the id of the pool. If the pool has no
+// explicit id, the ids of its tasks are used, so that the draw changes
+// if tasks are added to or removed from the pool.
+type Pool struct {
+	Id          string `xml:"id,attr"`
+	Draw        int    `xml:"draw,attr"`
+	Task        []*Task
+	UseTemplate []*UseTemplate
+	id          string
+	pos         position
+	mutex       sync.Mutex
+	drawnBySeed map[string]map[*Task]bool
+}
+
+// Selection determines which tasks of the pools are visible to a user.
+type Selection struct {
+	// Seed is used to draw the tasks, so that a user always gets the same tasks
+	Seed string
+	// All is set if all tasks of the pools are visible
+	All bool
+}
+
+// initPools adds the tasks of the pools to the tasks of the chapter at the
+// position of the pool. This way the pool tasks are treated like all other
+// tasks, and only the visibility depends on the selection.
+func (c *Chapter) initPools() error {
+	for _, p := range c.Pool {
+		if len(p.Task) == 0 {
+			return p.pos.errorf("empty pool in chapter '%s'", c.Title)
+		}
+		if p.Draw <= 0 || p.Draw > len(p.Task) {
+			return p.pos.errorf("pool in chapter '%s' contains %d tasks, so draw needs to be in the range [1,%d]", c.Title, len(p.Task), len(p.Task))
+		}
+		for _, t := range p.Task {
+			t.pool = p
+			c.Task = append(c.Task, t)
+		}
+	}
+	sortBySource(c.Task)
+	return nil
+}
+
+// initPoolIds creates the ids of the pools used to draw the tasks.
+// It is called after the tasks are initialized, because the ids of
+// the tasks are required.
+func (c *Chapter) initPoolIds() error {
+	ids := map[string]bool{}
+	for _, p := range c.Pool {
+		key := p.Id
+		if key == "" {
+			var tids []string
+			for _, t := range p.Task {
+				tids = append(tids, string(t.tid))
+			}
+			key = strings.Join(tids, ",")
+		}
+		p.id = c.StateId() + ":" + key
+		if ids[p.id] {
+			return p.pos.errorf("duplicate pool id '%s' in chapter '%s'", p.Id, c.Title)
+		}
+		ids[p.id] = true
+	}
+	return nil
+}
+
+// drawn returns the tasks drawn for the given seed.
+// The draw is cached, so it is only computed once per seed.
+func (p *Pool) drawn(seed string) map[*Task]bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if d, ok := p.drawnBySeed[seed]; ok {
+		return d
+	}
+
+	r := newRand(seed + p.id)
+	d := make(map[*Task]bool, p.Draw)
+	for _, i := range r.Perm(len(p.Task))[:p.Draw] {
+		d[p.Task[i]] = true
+	}
+	if p.drawnBySeed == nil {
+		p.drawnBySeed = map[string]map[*Task]bool{}
+	}
+	p.drawnBySeed[seed] = d
+	return d
+}
+
+// IsSelected returns true if the task is visible with the given selection.
+// Tasks which are not part of a pool are always visible.
+func (t *Task) IsSelected(s Selection) bool {
+	if t.pool == nil || s.All {
+		return true
+	}
+	return t.pool.drawn(s.Seed)[t]
+}
+
+// SelectedTasks returns the tasks of this chapter visible with the given selection
+func (c *Chapter) SelectedTasks(s Selection) []*Task {
+	var tasks []*Task
+	for _, t := range c.Task {
+		if t.IsSelected(s) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// Selected iterates over all tasks of the chapter and its sub chapters
+// which are visible with the given selection.
+func (c *Chapter) Selected(s Selection) func(yield func(task *Task) bool) {
+	return func(yield func(task *Task) bool) {
+		for task := range c.Iter {
+			if task.IsSelected(s) && !yield(task) {
+				return
+			}
+		}
+	}
+}
+
+// Tasks returns the number of tasks visible with the given selection
+func (c *Chapter) Tasks(s Selection) int {
+	n := 0
+	for range c.Selected(s) {
+		n++
+	}
+	return n
+}
+
+// MaxPoints returns the points which can be reached in this chapter
+func (c *Chapter) MaxPoints(s Selection) float64 {
+	p := 0.0
+	for task := range c.Selected(s) {
+		p += task.MaxPoints()
+	}
+	return p
+}
+
+// MaxPoints returns the points which can be reached in this lecture
+func (l *Lecture) MaxPoints(s Selection) float64 {
+	p := 0.0
+	for _, c := range l.Chapter {
+		p += c.MaxPoints(s)
+	}
+	return p
+}
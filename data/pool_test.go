@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func poolTasks(tasks int) string {
+	var b strings.Builder
+	for i := 0; i < tasks; i++ {
+		b.WriteString(fmt.Sprintf(`
+            <Task>
+                <Name>Pool %d</Name>
+                <Question>Frage</Question>
+                <Input id="a" type="number">
+                    <Label>a:</Label>
+                    <Validator>
+                        <Expression>cmpValues(%d,answer.a,1)</Expression>
+                    </Validator>
+                </Input>
+            </Task>`, i, i))
+	}
+	return b.String()
+}
+
+func poolLecture(draw int, tasks int) string {
+	return fmt.Sprintf(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Fest</Question>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+        <Pool draw="%d">%s
+        </Pool>
+	</Chapter>
+</Lecture>`, draw, poolTasks(tasks))
+}
+
+func TestPool(t *testing.T) {
+	lecture, err := readLectureToTest(poolLecture(3, 10))
+	assert.NoError(t, err)
+	chapter := lecture.Chapter[0]
+
+	assert.Equal(t, 11, len(chapter.Task))
+	assert.Equal(t, 11, lecture.TaskCount())
+	assert.Equal(t, 11, chapter.Tasks(Selection{All: true}))
+
+	distinct := map[string]bool{}
+	for _, seed := range []string{"a", "b", "c", "d", "e"} {
+		sel := Selection{Seed: seed}
+		tasks := chapter.SelectedTasks(sel)
+		assert.Equal(t, 4, len(tasks))
+		assert.Equal(t, 4, chapter.Tasks(sel))
+		assert.Equal(t, 4.0, chapter.MaxPoints(sel))
+		assert.Equal(t, chapter.Task[0], tasks[0], "fixed task is always selected")
+		assert.Equal(t, tasks, chapter.SelectedTasks(sel), "selection is deterministic")
+
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		distinct[strings.Join(names, ",")] = true
+	}
+	assert.True(t, len(distinct) > 1, "different seeds draw different tasks")
+}
+
+func TestPoolOrder(t *testing.T) {
+	task := func(name string) string {
+		return `
+            <Task>
+                <Name>` + name + `</Name>
+                <Question>Frage</Question>
+                <Input id="a" type="number">
+                    <Label>a:</Label>
+                    <Validator>
+                        <Expression>cmpValues(1,answer.a,1)</Expression>
+                    </Validator>
+                </Input>
+            </Task>`
+	}
+	lecture, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>` + task("first") + `
+        <Pool draw="1">` + task("pool a") + task("pool b") + `
+        </Pool>` + task("last") + `
+	</Chapter>
+</Lecture>`)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, task := range lecture.Chapter[0].Task {
+		names = append(names, task.Name)
+	}
+	assert.Equal(t, []string{"Frage 1: first", "Frage 2: pool a", "Frage 3: pool b", "Frage 4: last"}, names)
+}
+
+func TestPoolInit(t *testing.T) {
+	tests := []struct {
+		draw, tasks int
+		err         string
+	}{
+		{0, 3, "draw needs to be in the range [1,3]"},
+		{4, 3, "draw needs to be in the range [1,3]"},
+		{1, 0, "empty pool"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d of %d", tt.draw, tt.tasks), func(t *testing.T) {
+			_, err := readLectureToTest(poolLecture(tt.draw, tt.tasks))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestPoolIdIndependentOfPosition(t *testing.T) {
+	chapter := func(id string) string {
+		return `
+    <Chapter id="` + id + `">
+        <Title>Kapitel ` + id + `</Title>
+        <Pool draw="2">` + poolTasks(5) + `
+        </Pool>
+	</Chapter>`
+	}
+	lecture := func(chapters ...string) string {
+		return `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>` + strings.Join(chapters, "") + `
+</Lecture>`
+	}
+
+	l1, err := readLectureToTest(lecture(chapter("b")))
+	assert.NoError(t, err)
+	l2, err := readLectureToTest(lecture(chapter("a"), chapter("b")))
+	assert.NoError(t, err)
+
+	for _, seed := range []string{"a", "b", "c", "d", "e"} {
+		sel := Selection{Seed: seed}
+		var ids1, ids2 []TaskId
+		for _, task := range l1.Chapter[0].SelectedTasks(sel) {
+			ids1 = append(ids1, task.TID())
+		}
+		for _, task := range l2.Chapter[1].SelectedTasks(sel) {
+			ids2 = append(ids2, task.TID())
+		}
+		assert.Equal(t, ids1, ids2)
+	}
+}
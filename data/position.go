@@ -0,0 +1,209 @@
+package data
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// position is a position in a xml source file
+type position struct {
+	file string
+	line int
+	col  int
+}
+
+func newPosition(d *xml.Decoder) position {
+	line, col := d.InputPos()
+	return position{line: line, col: col}
+}
+
+func (p position) String() string {
+	file := p.file
+	if file == "" {
+		file = "xml"
+	}
+	if p.col > 0 {
+		return fmt.Sprintf("%s:%d:%d", file, p.line, p.col)
+	}
+	return fmt.Sprintf("%s:%d", file, p.line)
+}
+
+// before returns true if the position is located before the given position
+func (p position) before(o position) bool {
+	if p.line != o.line {
+		return p.line < o.line
+	}
+	return p.col < o.col
+}
+
+// errorf creates an error which is prefixed by the position
+func (p position) errorf(format string, a ...any) error {
+	return fmt.Errorf("%s: "+format, append([]any{p}, a...)...)
+}
+
+var lineInExpression = regexp.MustCompile(`in line ([0-9]+)`)
+
+// exprError maps an error returned by the parser to the position in
+// the xml file. The parser reports the line number relative to the
+// beginning of the expression.
+func (p position) exprError(err error) error {
+	return p.exprErrorOffset(err, 0)
+}
+
+// exprErrorOffset is used if the expression is preceded by the
+// given number of lines which are not part of the xml element.
+func (p position) exprErrorOffset(err error, lines int) error {
+	pos := p
+	if m := lineInExpression.FindStringSubmatch(err.Error()); m != nil {
+		if l, e := strconv.Atoi(m[1]); e == nil && l-lines > 1 {
+			pos.line += l - lines - 1
+			pos.col = 0
+		}
+	}
+	return pos.errorf("%w", err)
+}
+
+// source is a string which also contains the position in the xml file
+type source struct {
+	text string
+	pos  position
+}
+
+func (s *source) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s.pos = newPosition(d)
+	return d.DecodeElement(&s.text, &start)
+}
+
+func (c *Chapter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Chapter
+	c.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(c), &start)
+}
+
+func (t *Task) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Task
+	t.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(t), &start)
+}
+
+func (p *Pool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Pool
+	p.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(p), &start)
+}
+
+func (t *TaskTemplate) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain TaskTemplate
+	t.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(t), &start)
+}
+
+func (i *Input) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Input
+	i.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(i), &start)
+}
+
+func (f *Functions) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Functions
+	f.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(f), &start)
+}
+
+func (p *Param) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Param
+	p.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(p), &start)
+}
+
+func (m *Mistake) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Mistake
+	m.pos = newPosition(d)
+	return d.DecodeElement((*Plain)(m), &start)
+}
+
+func (v *Validator) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Plain Validator
+	v.pos = newPosition(d)
+	// the outer Expression field hides the field of the embedded
+	// validator, which allows to store the position of the expression
+	aux := struct {
+		*Plain
+		Expression source
+	}{Plain: (*Plain)(v)}
+	err := d.DecodeElement(&aux, &start)
+	v.Expression = aux.Expression.text
+	v.exprPos = aux.Expression.pos
+	return err
+}
+
+// setFile sets the file name in all positions
+func (l *Lecture) setFile(file string) {
+	for _, f := range l.Functions {
+		f.pos.file = file
+	}
+	setTemplateFile(l.TaskTemplate, file)
+	l.Chapter.setFile(file)
+}
+
+func (c ChapterList) setFile(file string) {
+	for _, ch := range c {
+		ch.setFile(file)
+	}
+}
+
+func (c *Chapter) setFile(file string) {
+	c.pos.file = file
+	for _, f := range c.Functions {
+		f.pos.file = file
+	}
+	setTemplateFile(c.TaskTemplate, file)
+	for _, u := range c.UseTemplate {
+		u.pos.file = file
+	}
+	c.Chapter.setFile(file)
+	for _, t := range c.Task {
+		t.setFile(file)
+	}
+	for _, p := range c.Pool {
+		p.pos.file = file
+		for _, t := range p.Task {
+			t.setFile(file)
+		}
+		for _, u := range p.UseTemplate {
+			u.pos.file = file
+		}
+	}
+}
+
+func setTemplateFile(list []*TaskTemplate, file string) {
+	for _, t := range list {
+		t.pos.file = file
+	}
+}
+
+func (t *Task) setFile(file string) {
+	t.pos.file = file
+	t.Validator.setFile(file)
+	for _, p := range t.Param {
+		p.pos.file = file
+	}
+	for _, i := range t.Input {
+		i.pos.file = file
+		i.Validator.setFile(file)
+	}
+}
+
+func (v *Validator) setFile(file string) {
+	if v != nil {
+		v.pos.file = file
+		v.exprPos.file = file
+		v.FollowUp.pos.file = file
+		for _, m := range v.Mistake {
+			m.pos.file = file
+			m.Condition.pos.file = file
+		}
+	}
+}
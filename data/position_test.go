@@ -0,0 +1,111 @@
+package data
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPositions(t *testing.T) {
+	tests := []struct {
+		name          string
+		xml           string
+		expectedError string
+	}{
+		{"noInput", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+        </Task>
+	</Chapter>
+</Lecture>`, "xml:7:15: no input"},
+		{"noLabel", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="val1" type="text">
+                <Validator>
+                    <Expression>cmpValues(40,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`, "xml:9:42: no label"},
+		{"parseError", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>cmpValues(40,
+                      answer.val1 1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`, "xml:13: error parsing expression"},
+		{"missingInclude", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter file="chapter.inc"/>
+</Lecture>`, "xml:5:34: chapter reference chapter.inc not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(tt.xml)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestPositionInInclude(t *testing.T) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	addFile := func(name, content string) {
+		w, err := z.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	addFile("lecture.xml", `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter file="chapter.inc"/>
+</Lecture>`)
+	addFile("chapter.inc", `<Chapter>
+    <Title>Gleichstromkreise</Title>
+    <Task>
+        <Question>Frage</Question>
+        <Input id="val1" type="text">
+            <Label>Wert:</Label>
+            <Validator>
+                <Expression>cmpValues(40,answer.val2,1)</Expression>
+            </Validator>
+        </Input>
+    </Task>
+</Chapter>`)
+	assert.NoError(t, z.Close())
+
+	_, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "chapter.inc:7:24: invalid expression")
+	}
+}
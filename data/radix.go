@@ -0,0 +1,442 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// radixPrefixes are the prefixes which select the radix of an integer
+var radixPrefixes = []struct {
+	prefix string
+	radix  int
+}{{"0b", 2}, {"0o", 8}, {"0x", 16}}
+
+// integerLiteral is an integer entered by the user
+type integerLiteral struct {
+	negative bool
+	// digits contains the digits without prefix and separators
+	digits string
+	// radix is the radix selected by a prefix, zero if there is no prefix
+	radix int
+	// prefix is the prefix selecting the radix
+	prefix string
+}
+
+// splitInteger splits an integer entered by the user into its parts.
+// Spaces and underscores can be used to group the digits.
+func splitInteger(s string) (integerLiteral, error) {
+	str := strings.NewReplacer(" ", "", "_", "", "—", "-").Replace(strings.TrimSpace(s))
+	if str == "" {
+		return integerLiteral{}, GuiError{message: "Die Eingabe ist leer!"}
+	}
+
+	var l integerLiteral
+	if rest, ok := strings.CutPrefix(str, "-"); ok {
+		l.negative = true
+		str = rest
+	}
+	for _, p := range radixPrefixes {
+		if len(str) > len(p.prefix) && strings.EqualFold(str[:len(p.prefix)], p.prefix) {
+			l.radix = p.radix
+			l.prefix = strings.ToUpper(str[:len(p.prefix)])
+			str = str[len(p.prefix):]
+			break
+		}
+	}
+	if str == "" {
+		return integerLiteral{}, GuiError{message: fmt.Sprintf("'%s' ist keine gültige ganze Zahl!", s)}
+	}
+	l.digits = strings.ToUpper(str)
+	return l, nil
+}
+
+// withoutPrefix returns the literal read as digits of the given radix,
+// including the prefix. This is possible if the letter of the prefix is a
+// valid digit, e.g. a hexadecimal number like 0B12 starts with a binary prefix.
+func (l integerLiteral) withoutPrefix(radix int) (integerLiteral, bool) {
+	if l.radix == 0 || l.radix == radix {
+		return l, false
+	}
+	if _, err := strconv.ParseUint(l.prefix, radix, 64); err != nil {
+		return l, false
+	}
+	return integerLiteral{negative: l.negative, digits: l.prefix + l.digits}, true
+}
+
+// value returns the value of the literal in the given radix
+func (l integerLiteral) value(radix int) (int64, bool) {
+	u, err := strconv.ParseUint(l.digits, radix, 64)
+	if err != nil || u > math.MaxInt64 {
+		return 0, false
+	}
+	if l.negative {
+		return -int64(u), true
+	}
+	return int64(u), true
+}
+
+// natural returns the value of the literal using the radix given by its
+// prefix, or in decimal if there is no prefix.
+func (l integerLiteral) natural() (int64, bool) {
+	if l.radix == 0 {
+		return l.value(10)
+	}
+	return l.value(l.radix)
+}
+
+// parseInteger parses an integer entered by the user
+func parseInteger(s string) (int64, error) {
+	l, err := splitInteger(s)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := l.natural()
+	if !ok {
+		return 0, GuiError{message: fmt.Sprintf("'%s' ist keine gültige ganze Zahl!", s)}
+	}
+	return v, nil
+}
+
+// radixName returns the name of the number system used in messages
+func radixName(radix int) string {
+	switch radix {
+	case 2:
+		return "Binärsystem"
+	case 8:
+		return "Oktalsystem"
+	case 10:
+		return "Dezimalsystem"
+	case 16:
+		return "Hexadezimalsystem"
+	default:
+		return fmt.Sprintf("Zahlensystem zur Basis %d", radix)
+	}
+}
+
+// bitMask returns a mask with the given number of bits set
+func bitMask(bits int) uint64 {
+	if bits >= 64 {
+		return math.MaxUint64
+	}
+	return 1<<bits - 1
+}
+
+// checkRadix checks the radix and bit width passed to a function
+func checkRadix(radix, bits int) error {
+	if radix < 2 || radix > 36 {
+		return fmt.Errorf("radix %d is not in the range [2,36]", radix)
+	}
+	if bits < 0 || bits > 64 {
+		return fmt.Errorf("bit width %d is not in the range [0,64]", bits)
+	}
+	return nil
+}
+
+// twosComplement returns the bit pattern of the value in two's complement
+// with the given number of bits. Unsigned values up to the maximum
+// pattern are allowed as well.
+func twosComplement(v int64, bits int) (uint64, error) {
+	if bits < 64 && (v < -(1<<(bits-1)) || v > int64(bitMask(bits))) {
+		return 0, fmt.Errorf("value %d does not fit in %d bits", v, bits)
+	}
+	return uint64(v) & bitMask(bits), nil
+}
+
+// toRadix formats the value in the given radix.
+// If bits is larger than zero, the bit pattern of the two's complement
+// is formatted using all digits required for the given number of bits.
+func toRadix(v int64, radix, bits int) (string, error) {
+	if err := checkRadix(radix, bits); err != nil {
+		return "", err
+	}
+	if bits == 0 {
+		return strings.ToUpper(strconv.FormatInt(v, radix)), nil
+	}
+	p, err := twosComplement(v, bits)
+	if err != nil {
+		return "", err
+	}
+	digits := len(strconv.FormatUint(bitMask(bits), radix))
+	s := strings.ToUpper(strconv.FormatUint(p, radix))
+	return strings.Repeat("0", digits-len(s)) + s, nil
+}
+
+// cmpRadix checks if the answer is the expected value in the given radix.
+// If bits is larger than zero, the answer has to be the two's complement
+// bit pattern with the given number of bits.
+// If the value is right but given in a different representation, a
+// message is returned.
+func cmpRadix(expected int64, answer string, radix, bits int) (value.Value, error) {
+	if err := checkRadix(radix, bits); err != nil {
+		return nil, err
+	}
+	var pattern uint64
+	if bits > 0 {
+		var err error
+		pattern, err = twosComplement(expected, bits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := splitInteger(answer)
+	if err != nil {
+		return nil, err
+	}
+	if plain, ok := l.withoutPrefix(radix); ok {
+		if r := cmpRadixLiteral(expected, pattern, plain, radix, bits); r == value.Bool(true) {
+			return r, nil
+		}
+	}
+	return cmpRadixLiteral(expected, pattern, l, radix, bits), nil
+}
+
+// cmpRadixLiteral compares the literal with the expected value, see cmpRadix
+func cmpRadixLiteral(expected int64, pattern uint64, l integerLiteral, radix, bits int) value.Value {
+	rightRadix := l.radix == 0 || l.radix == radix
+	if rightRadix && !(bits > 0 && l.negative) {
+		if u, err := strconv.ParseUint(l.digits, radix, 64); err == nil {
+			if bits == 0 {
+				if v, ok := l.value(radix); ok && v == expected {
+					return value.Bool(true)
+				}
+			} else {
+				if u > bitMask(bits) {
+					return value.String(fmt.Sprintf("Die Zahl passt nicht in %d Bit!", bits))
+				}
+				if u == pattern {
+					return value.Bool(true)
+				}
+			}
+		}
+	}
+
+	if rightRadix && bits > 0 {
+		if v, ok := l.value(radix); ok && v == expected {
+			return value.String(fmt.Sprintf("Der Wert ist richtig, aber nicht als %d-Bit Zweierkomplement angegeben!", bits))
+		}
+	}
+	if v, ok := l.natural(); ok && v == expected {
+		return value.String(fmt.Sprintf("Der Wert ist richtig, aber nicht im %s angegeben!", radixName(radix)))
+	}
+	return value.Bool(false)
+}
+
+// floatPattern returns the IEEE-754 bit pattern of the value
+func floatPattern(v float64, bits int) (uint64, error) {
+	switch bits {
+	case 32:
+		return uint64(math.Float32bits(float32(v))), nil
+	case 64:
+		return math.Float64bits(v), nil
+	default:
+		return 0, fmt.Errorf("IEEE-754 numbers need 32 or 64 bits, not %d", bits)
+	}
+}
+
+// ieee returns the IEEE-754 bit pattern of the value in hexadecimal
+func ieee(v float64, bits int) (string, error) {
+	p, err := floatPattern(v, bits)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*X", bits/4, p), nil
+}
+
+// cmpIEEE checks if the answer is the IEEE-754 bit pattern of the expected value.
+// The pattern can be given in hexadecimal or in binary. A binary pattern
+// needs the prefix 0b or all digits.
+func cmpIEEE(expected float64, answer string, bits int) (value.Value, error) {
+	want, err := floatPattern(expected, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user has entered the value instead of the bit pattern
+	isValue := func() bool {
+		f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(answer), ",", "."), 64)
+		if err != nil {
+			return false
+		}
+		p, _ := floatPattern(f, bits)
+		return p == want
+	}
+
+	l, err := splitInteger(answer)
+	if err != nil || l.negative {
+		if isValue() {
+			return value.String("Der Wert ist richtig, aber nicht als IEEE-754 Bitmuster angegeben!"), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return value.Bool(false), nil
+	}
+
+	// a hexadecimal pattern like 0B12... starts with a binary prefix
+	if plain, ok := l.withoutPrefix(16); ok {
+		if u, err := strconv.ParseUint(plain.digits, 16, 64); err == nil && u == want {
+			return value.Bool(true), nil
+		}
+	}
+
+	radix := l.radix
+	if radix == 0 {
+		radix = 16
+		if len(l.digits) == bits && strings.Trim(l.digits, "01") == "" {
+			radix = 2
+		}
+	}
+	if radix != 2 && radix != 16 {
+		return value.String("Das Bitmuster muss binär oder hexadezimal angegeben werden!"), nil
+	}
+
+	u, err := strconv.ParseUint(l.digits, radix, 64)
+	if err == nil && u > bitMask(bits) {
+		return value.String(fmt.Sprintf("Das Bitmuster hat mehr als %d Bit!", bits)), nil
+	}
+	if err == nil && u == want {
+		return value.Bool(true), nil
+	}
+	if isValue() {
+		return value.String("Der Wert ist richtig, aber nicht als IEEE-754 Bitmuster angegeben!"), nil
+	}
+	return value.Bool(false), nil
+}
+
+// toInteger converts a value to an integer.
+// Strings are parsed as integers entered by the user.
+func toInteger(v value.Value) (int64, error) {
+	switch v := v.(type) {
+	case value.String:
+		return parseInteger(string(v))
+	case value.Int:
+		return int64(v), nil
+	default:
+		if f, ok := v.ToFloat(); ok && f == math.Trunc(f) {
+			return int64(f), nil
+		}
+		return 0, fmt.Errorf("expected an integer, got %v", v)
+	}
+}
+
+// toSmallInt converts a value to an int used as radix or bit width
+func toSmallInt(v value.Value) (int, error) {
+	if i, ok := v.ToInt(); ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("expected an integer, got %v", v)
+}
+
+func addRadixFunctions(f *funcGen.FunctionGenerator[value.Value]) {
+	f.AddStaticFunction("parseInt", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			str, ok := stack.Get(0).(value.String)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %v", stack.Get(0))
+			}
+			v, err := parseInteger(string(str))
+			if err != nil {
+				return nil, err
+			}
+			return value.Int(v), nil
+		},
+		Args:   1,
+		IsPure: true,
+	}.SetDescription("str", "parses an integer. The prefixes 0b, 0o and 0x select binary, octal and hexadecimal numbers"))
+	f.AddStaticFunction("toRadix", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			v, err := toInteger(stack.Get(0))
+			if err != nil {
+				return nil, err
+			}
+			radix, err := toSmallInt(stack.Get(1))
+			if err != nil {
+				return nil, err
+			}
+			bits, err := toSmallInt(stack.Get(2))
+			if err != nil {
+				return nil, err
+			}
+			s, err := toRadix(v, radix, bits)
+			if err != nil {
+				return nil, err
+			}
+			return value.String(s), nil
+		},
+		Args:   3,
+		IsPure: true,
+	}.SetDescription("value", "radix", "bits",
+		"formats an integer in the given radix. If bits is larger than zero, the two's complement "+
+			"with the given number of bits is formatted"))
+	f.AddStaticFunction("cmpRadix", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, err := toInteger(stack.Get(0))
+			if err != nil {
+				return nil, err
+			}
+			answer, ok := stack.Get(1).(value.String)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %v", stack.Get(1))
+			}
+			radix, err := toSmallInt(stack.Get(2))
+			if err != nil {
+				return nil, err
+			}
+			bits, err := toSmallInt(stack.Get(3))
+			if err != nil {
+				return nil, err
+			}
+			return cmpRadix(expected, string(answer), radix, bits)
+		},
+		Args:   4,
+		IsPure: true,
+	}.SetDescription("expected", "is", "radix", "bits",
+		"checks if an integer is given in the given radix. If bits is larger than zero, "+
+			"the two's complement with the given number of bits is expected. "+
+			"A message is returned if the value is right but given in a different representation"))
+	f.AddStaticFunction("ieee", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			v, ok := stack.Get(0).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(0))
+			}
+			bits, err := toSmallInt(stack.Get(1))
+			if err != nil {
+				return nil, err
+			}
+			s, err := ieee(v, bits)
+			if err != nil {
+				return nil, err
+			}
+			return value.String(s), nil
+		},
+		Args:   2,
+		IsPure: true,
+	}.SetDescription("value", "bits", "returns the hexadecimal IEEE-754 bit pattern of a number with 32 or 64 bits"))
+	f.AddStaticFunction("cmpIEEE", funcGen.Function[value.Value]{
+		Func: func(stack funcGen.Stack[value.Value], closureStore []value.Value) (value.Value, error) {
+			expected, ok := stack.Get(0).ToFloat()
+			if !ok {
+				return nil, fmt.Errorf("expected a number, got %v", stack.Get(0))
+			}
+			answer, ok := stack.Get(1).(value.String)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %v", stack.Get(1))
+			}
+			bits, err := toSmallInt(stack.Get(2))
+			if err != nil {
+				return nil, err
+			}
+			return cmpIEEE(expected, string(answer), bits)
+		},
+		Args:   3,
+		IsPure: true,
+	}.SetDescription("expected", "is", "bits",
+		"checks if the IEEE-754 bit pattern with 32 or 64 bits of a number is given in hexadecimal or binary. "+
+			"A message is returned if the value is given instead of the bit pattern"))
+}
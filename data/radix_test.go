@@ -0,0 +1,130 @@
+package data
+
+import (
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		str  string
+		want int64
+	}{
+		{"42", 42},
+		{"-42", -42},
+		{"0b1011", 11},
+		{"0B1011", 11},
+		{"0b1011_0011", 0xb3},
+		{"0b 1011 0011", 0xb3},
+		{"0o17", 15},
+		{"0x1F", 31},
+		{"0x1f", 31},
+		{"-0x1F", -31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			v, err := parseInteger(tt.str)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+
+	for _, str := range []string{"", "0x", "0b12", "1.5", "abc"} {
+		_, err := parseInteger(str)
+		assert.Error(t, err, str)
+	}
+}
+
+func TestToRadix(t *testing.T) {
+	tests := []struct {
+		v     int64
+		radix int
+		bits  int
+		want  string
+	}{
+		{31, 16, 0, "1F"},
+		{-31, 16, 0, "-1F"},
+		{5, 2, 0, "101"},
+		{5, 2, 8, "00000101"},
+		{-5, 2, 8, "11111011"},
+		{-1, 16, 16, "FFFF"},
+		{255, 16, 8, "FF"},
+		{-1, 8, 8, "377"},
+		{-1, 16, 64, "FFFFFFFFFFFFFFFF"},
+	}
+	for _, tt := range tests {
+		s, err := toRadix(tt.v, tt.radix, tt.bits)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, s)
+	}
+
+	_, err := toRadix(256, 16, 8)
+	assert.Error(t, err)
+	_, err = toRadix(-129, 16, 8)
+	assert.Error(t, err)
+	_, err = toRadix(1, 1, 8)
+	assert.Error(t, err)
+}
+
+func TestIEEE(t *testing.T) {
+	s, err := ieee(1, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, "3F800000", s)
+	s, err = ieee(-2.5, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, "C004000000000000", s)
+	_, err = ieee(1, 16)
+	assert.Error(t, err)
+}
+
+func TestRadixFunctions(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result value.Value
+	}{
+		{`parseInt("0x1F")`, value.Int(31)},
+		{`toRadix(31,16,0)`, value.String("1F")},
+		{`toRadix("0b101",10,0)`, value.String("5")},
+		{`cmpRadix(31,"1F",16,0)`, value.Bool(true)},
+		{`cmpRadix(31,"0x1f",16,0)`, value.Bool(true)},
+		{`cmpRadix(31,"1E",16,0)`, value.Bool(false)},
+		{`cmpRadix(31,"31",16,0)`, value.String("Der Wert ist richtig, aber nicht im Hexadezimalsystem angegeben!")},
+		{`cmpRadix(31,"0b11111",16,0)`, value.String("Der Wert ist richtig, aber nicht im Hexadezimalsystem angegeben!")},
+		{`cmpRadix(2834,toRadix(2834,16,16),16,16)`, value.Bool(true)},
+		{`cmpRadix(2834,"0B12",16,0)`, value.Bool(true)},
+		{`cmpRadix(5,"0b101",16,0)`, value.String("Der Wert ist richtig, aber nicht im Hexadezimalsystem angegeben!")},
+		{`cmpRadix(11,"1011",2,0)`, value.Bool(true)},
+		{`cmpRadix(-5,"11111011",2,8)`, value.Bool(true)},
+		{`cmpRadix(-5,"1111 1011",2,8)`, value.Bool(true)},
+		{`cmpRadix(-5,"FB",16,8)`, value.Bool(true)},
+		{`cmpRadix(5,"101",2,8)`, value.Bool(true)},
+		{`cmpRadix(-5,"-101",2,8)`, value.String("Der Wert ist richtig, aber nicht als 8-Bit Zweierkomplement angegeben!")},
+		{`cmpRadix(-5,"-5",2,8)`, value.String("Der Wert ist richtig, aber nicht im Binärsystem angegeben!")},
+		{`cmpRadix(-5,"111111011",2,8)`, value.String("Die Zahl passt nicht in 8 Bit!")},
+		{`cmpRadix(-5,"1011",2,8)`, value.Bool(false)},
+		{`cmpIEEE(1,"3F800000",32)`, value.Bool(true)},
+		{`cmpIEEE(1,"0x3f80_0000",32)`, value.Bool(true)},
+		{`cmpIEEE(1,"0011 1111 1000 0000 0000 0000 0000 0000",32)`, value.Bool(true)},
+		{`cmpIEEE(-2.5,"C004000000000000",64)`, value.Bool(true)},
+		{`cmpIEEE(1,"3F800001",32)`, value.Bool(false)},
+		{`cmpIEEE(2.465190328815662e-32,ieee(2.465190328815662e-32,32),32)`, value.Bool(true)},
+		{`cmpIEEE(2.465190328815662e-32,"0B00 0000",32)`, value.Bool(true)},
+		{`cmpIEEE(1,"1",32)`, value.String("Der Wert ist richtig, aber nicht als IEEE-754 Bitmuster angegeben!")},
+		{`cmpIEEE(-2.5,"-2,5",32)`, value.String("Der Wert ist richtig, aber nicht als IEEE-754 Bitmuster angegeben!")},
+		{`cmpIEEE(1,"0o777",32)`, value.String("Das Bitmuster muss binär oder hexadezimal angegeben werden!")},
+		{`cmpIEEE(1,"13F800000",32)`, value.String("Das Bitmuster hat mehr als 32 Bit!")},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.expr, func(t *testing.T) {
+			f, err := myParser.Generate(tst.expr)
+			assert.NoError(t, err)
+			if f != nil {
+				r, err := f.Eval()
+				assert.NoError(t, err)
+				assert.Equal(t, tst.result, r)
+			}
+		})
+	}
+}
@@ -41,6 +41,21 @@ func ReadLectures(folder string) (*Lectures, error) {
 	return &lectures, nil
 }
 
+// ReadLecture reads a lecture from a folder or a zip file.
+func ReadLecture(path string) (*Lecture, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lecture: %w", err)
+	}
+	if fi.IsDir() {
+		return readFolder(path)
+	}
+	if filepath.Ext(path) == ".zip" {
+		return readZipFile(path)
+	}
+	return nil, fmt.Errorf("%s is neither a folder nor a zip file", path)
+}
+
 func readFolder(folder string) (*Lecture, error) {
 	files, err := os.ReadDir(folder)
 	if err != nil {
@@ -66,6 +81,7 @@ func readFolder(folder string) (*Lecture, error) {
 				if err != nil {
 					return nil, fmt.Errorf("error parsing file %s: %w", path, err)
 				}
+				lecture.setFile(f.Name())
 			} else {
 				data, err := os.ReadFile(path)
 				if err != nil {
@@ -123,6 +139,7 @@ func ReadZip(r io.ReaderAt, size int64) (*Lecture, error) {
 			if err != nil {
 				return nil, fmt.Errorf("error parsing file %s: %w", f.Name, err)
 			}
+			lecture.setFile(f.Name)
 		} else {
 			zData, err := f.Open()
 			if err != nil {
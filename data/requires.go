@@ -0,0 +1,133 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Requirement is a chapter which needs to be completed up to
+// the given threshold before a chapter becomes available.
+type Requirement struct {
+	Chapter *Chapter
+	// Threshold is the fraction of tasks which need to be completed
+	Threshold float64
+}
+
+// Percent returns the threshold in percent
+func (r *Requirement) Percent() int {
+	return int(r.Threshold*100 + 0.5)
+}
+
+// Requirements returns the requirements of this chapter
+func (c *Chapter) Requirements() []*Requirement {
+	return c.requires
+}
+
+// initRequirements resolves the requires attributes of all chapters.
+// A requirement is written as a chapter id or a chapter number like "2" or
+// "2.1" followed by an optional threshold in percent, e.g. "2:80".
+// Several requirements are separated by commas.
+func (l *Lecture) initRequirements() error {
+	ids := map[string]*Chapter{}
+	for _, c := range l.AllChapters() {
+		if c.Id == "" {
+			continue
+		}
+		if err := checkIdent(c.Id); err != nil {
+			return c.pos.errorf("invalid id '%s' in chapter '%s': %w", c.Id, c.Title, err)
+		}
+		if _, ok := ids[c.Id]; ok {
+			return c.pos.errorf("chapter id '%s' is used twice", c.Id)
+		}
+		ids[c.Id] = c
+	}
+
+	for _, c := range l.AllChapters() {
+		if strings.TrimSpace(c.Requires) == "" {
+			continue
+		}
+		for _, r := range strings.Split(c.Requires, ",") {
+			req, err := l.parseRequirement(strings.TrimSpace(r), ids)
+			if err != nil {
+				return c.pos.errorf("invalid requirement '%s' in chapter '%s': %w", strings.TrimSpace(r), c.Title, err)
+			}
+			if req.Chapter.contains(c) || c.contains(req.Chapter) {
+				return c.pos.errorf("chapter '%s' requires itself", c.Title)
+			}
+			c.requires = append(c.requires, req)
+		}
+	}
+
+	for _, c := range l.AllChapters() {
+		if err := c.checkCycle(map[*Chapter]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Lecture) parseRequirement(r string, ids map[string]*Chapter) (*Requirement, error) {
+	ref, thresholdStr, hasThreshold := strings.Cut(r, ":")
+	threshold := 1.0
+	if hasThreshold {
+		p, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(thresholdStr), "%"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold: %w", err)
+		}
+		if p <= 0 || p > 100 {
+			return nil, fmt.Errorf("threshold needs to be in the range [1,100]")
+		}
+		threshold = float64(p) / 100
+	}
+
+	ref = strings.TrimSpace(ref)
+	if c, ok := ids[ref]; ok {
+		return &Requirement{Chapter: c, Threshold: threshold}, nil
+	}
+
+	num, err := NewChapterNum(ref)
+	if err != nil {
+		return nil, fmt.Errorf("chapter '%s' not found", ref)
+	}
+	// chapter numbers are written one based
+	for i := range num {
+		num[i]--
+	}
+	c := l.Chapter._get(num)
+	if c == nil {
+		return nil, fmt.Errorf("chapter '%s' not found", ref)
+	}
+	return &Requirement{Chapter: c, Threshold: threshold}, nil
+}
+
+// contains returns true if the given chapter is this chapter or one of its sub chapters
+func (c *Chapter) contains(other *Chapter) bool {
+	for ; other != nil; other = other.ParentChapter {
+		if other == c {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCycle checks for cyclic requirements.
+// A chapter depends on its own requirements and on the requirements
+// of its parent chapters.
+func (c *Chapter) checkCycle(visiting map[*Chapter]bool) error {
+	if visiting[c] {
+		return c.pos.errorf("cyclic requirements found at chapter '%s'", c.Title)
+	}
+	visiting[c] = true
+	defer delete(visiting, c)
+
+	for _, r := range c.requires {
+		if err := r.Chapter.checkCycle(visiting); err != nil {
+			return err
+		}
+	}
+	if c.ParentChapter != nil {
+		return c.ParentChapter.checkCycle(visiting)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package data
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func requiresLecture(attr1, attr2, attr3 string) string {
+	return fmt.Sprintf(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter %s>
+        <Title>Grundlagen</Title>
+        <Task>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator><Expression>cmpValues(1,answer.a,1)</Expression></Validator>
+            </Input>
+        </Task>
+	</Chapter>
+    <Chapter %s>
+        <Title>Netzwerke</Title>
+        <Chapter %s>
+            <Title>Knoten</Title>
+            <Task>
+                <Input id="a" type="number">
+                    <Label>a:</Label>
+                    <Validator><Expression>cmpValues(2,answer.a,1)</Expression></Validator>
+                </Input>
+            </Task>
+        </Chapter>
+	</Chapter>
+</Lecture>`, attr1, attr2, attr3)
+}
+
+func TestRequires(t *testing.T) {
+	tests := []struct {
+		attr1, attr2, attr3 string
+		chapter             ChapterNum
+		req                 ChapterNum
+		percent             int
+	}{
+		{"", `requires="1"`, "", ChapterNum{1}, ChapterNum{0}, 100},
+		{"", `requires="1:80"`, "", ChapterNum{1}, ChapterNum{0}, 80},
+		{"", "", `requires="1 : 75%"`, ChapterNum{1, 0}, ChapterNum{0}, 75},
+		{`id="basics"`, "", `requires="basics:50"`, ChapterNum{1, 0}, ChapterNum{0}, 50},
+		{`requires="2.1:60"`, "", "", ChapterNum{0}, ChapterNum{1, 0}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attr1+tt.attr2+tt.attr3, func(t *testing.T) {
+			lecture, err := readLectureToTest(requiresLecture(tt.attr1, tt.attr2, tt.attr3))
+			if !assert.NoError(t, err) {
+				return
+			}
+			c, err := lecture.GetChapter(tt.chapter)
+			assert.NoError(t, err)
+			req, err := lecture.GetChapter(tt.req)
+			assert.NoError(t, err)
+			if assert.Equal(t, 1, len(c.Requirements())) {
+				assert.Equal(t, req, c.Requirements()[0].Chapter)
+				assert.Equal(t, tt.percent, c.Requirements()[0].Percent())
+			}
+		})
+	}
+}
+
+func TestRequiresInit(t *testing.T) {
+	tests := []struct {
+		attr1, attr2, attr3 string
+		err                 string
+	}{
+		{"", `requires="3"`, "", "chapter '3' not found"},
+		{"", `requires="unknown"`, "", "chapter 'unknown' not found"},
+		{"", `requires="1:0"`, "", "threshold needs to be in the range [1,100]"},
+		{"", `requires="1:101"`, "", "threshold needs to be in the range [1,100]"},
+		{"", `requires="1:x"`, "", "invalid threshold"},
+		{"", "", `requires="2"`, "requires itself"},
+		{"", `requires="2.1"`, "", "requires itself"},
+		{`requires="2"`, `requires="1"`, "", "cyclic requirements"},
+		{`requires="2.1"`, `requires="1"`, "", "cyclic requirements"},
+		{`id="a"`, `id="a"`, "", "chapter id 'a' is used twice"},
+		{`id="1a"`, "", "", "invalid id '1a'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attr1+tt.attr2+tt.attr3, func(t *testing.T) {
+			_, err := readLectureToTest(requiresLecture(tt.attr1, tt.attr2, tt.attr3))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/value"
+)
+
+// Result is the result of the validation of a task
+type Result struct {
+	// Messages contains the messages shown at the inputs.
+	// The id "_task_" is used for the messages concerning the whole task.
+	Messages map[InputId]string
+	// Score is the score reached in the range [0,1]
+	Score float64
+	// Mistakes contains the keys of the detected mistakes
+	Mistakes []string
+	accepted map[InputId]bool
+}
+
+func newResult() *Result {
+	return &Result{Messages: map[InputId]string{}, accepted: map[InputId]bool{}}
+}
+
+// Accepted returns true if the given input is known to be answered correctly
+func (r *Result) Accepted(id InputId) bool {
+	if r == nil {
+		return false
+	}
+	return r.accepted[id]
+}
+
+// mapMessages converts the map returned by a task validator to messages.
+// The values are messages or bools. An empty message or true means the
+// input is accepted, false means that the default message is shown.
+// The returned set contains the inputs explicitly accepted by true.
+func mapMessages(m value.Map) (map[InputId]string, map[InputId]bool, error) {
+	messages := map[InputId]string{}
+	accepted := map[InputId]bool{}
+	var err error
+	m.Iter(func(key string, v value.Value) bool {
+		id := InputId(key)
+		switch v := v.(type) {
+		case value.String:
+			if v != "" {
+				messages[id] = string(v)
+			}
+		case value.Bool:
+			if v {
+				accepted[id] = true
+			} else {
+				messages[id] = DefaultMessage
+			}
+		default:
+			err = fmt.Errorf("unexpected value %v for '%s'", v, key)
+			return false
+		}
+		return true
+	})
+	return messages, accepted, err
+}
+
+// mapResult handles the map returned by a task validator. The map contains
+// messages for the judged inputs which are not correct and a general
+// message using the key "_task_". Since "_task_" is not an identifier, it
+// is added by put("_task_", msg). If there is a general message, only the inputs
+// explicitly accepted by true count as correct.
+// The score is the weighted share of the accepted inputs.
+// The general message is returned, the other messages are added to the result.
+func (v *Validator) mapResult(m value.Map, judged []*Input, params Params, result *Result) (float64, string) {
+	messages, accepted, err := mapMessages(m)
+	if err != nil {
+		return 0, cleanupError(err)
+	}
+	general, hasGeneral := messages["_task_"]
+	for id := range messages {
+		if id != "_task_" && findInput(judged, id) == nil {
+			return 0, fmt.Sprintf("unexpected result: '%s' is not an input judged by the task validator", id)
+		}
+	}
+
+	var sum, weights float64
+	for _, i := range judged {
+		weights += i.weight()
+		if msg, ok := messages[i.Id]; ok {
+			result.Messages[i.Id] = params.Substitute(msg)
+		} else if accepted[i.Id] || !hasGeneral {
+			result.accepted[i.Id] = true
+			sum += i.weight()
+		}
+	}
+
+	if weights == 0 {
+		if len(messages) == 0 {
+			return 1, ""
+		}
+		return 0, general
+	}
+	return sum / weights, general
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const mapLecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Geben Sie zwei Zahlen mit der Summe 10 an, die erste soll größer sein.</Question>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+            </Input>
+            <Input id="b" type="number">
+                <Label>b:</Label>
+            </Input>
+            <Input id="c" type="number">
+                <Label>c:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.c,1)</Expression>
+                </Validator>
+            </Input>
+            <Validator>
+                <Expression>
+                    let a=num(answer.a);
+                    let b=num(answer.b);
+                    if abs(a+b-10)&lt;1e-6
+                    then {a: if a>b then "" else "a ist nicht größer als b!", b: true}
+                    else {b: b>0}.put("_task_", "Die Summe ist nicht 10!")
+                </Expression>
+                <Test a="6" b="4" ok="yes"/>
+                <Test a="4" b="6" message.a="a ist nicht größer als b!" message.b="" ok="no"/>
+                <Test a="4" b="4" message._task_="Die Summe ist nicht 10!"/>
+                <Test a="4" b="-4" message.b="Das ist nicht richtig!"/>
+            </Validator>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+func TestMapResult(t *testing.T) {
+	lecture, err := readLectureToTest(mapLecture)
+	assert.NoError(t, err)
+	task := lecture.Chapter[0].Task[0]
+
+	tests := []struct {
+		a, b     string
+		messages map[InputId]string
+		score    float64
+		accepted []InputId
+	}{
+		{"6", "4", map[InputId]string{}, 1, []InputId{"a", "b", "c"}},
+		{"4", "6", map[InputId]string{"a": "a ist nicht größer als b!"}, 2.0 / 3, []InputId{"b", "c"}},
+		{"4", "4", map[InputId]string{"_task_": "Die Summe ist nicht 10!"}, 2.0 / 3, []InputId{"b", "c"}},
+		{"4", "-4", map[InputId]string{"_task_": "Die Summe ist nicht 10!", "b": DefaultMessage}, 1.0 / 3, []InputId{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+","+tt.b, func(t *testing.T) {
+			result := task.Score(DataMap{"a": tt.a, "b": tt.b, "c": "1"}, nil, false)
+			assert.Equal(t, tt.messages, result.Messages)
+			assert.InDelta(t, tt.score, result.Score, 1e-6)
+			for _, id := range []InputId{"a", "b", "c"} {
+				assert.Equal(t, contains(tt.accepted, id), result.Accepted(id), id)
+			}
+		})
+	}
+}
+
+func contains(list []InputId, id InputId) bool {
+	for _, i := range list {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMapResultTest(t *testing.T) {
+	_, err := readLectureToTest(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Frage</Question>
+            <Input id="a" type="number">
+                <Label>a:</Label>
+            </Input>
+            <Validator>
+                <Expression>{a: if cmpValues(1,answer.a,1) then true else "falsch"}</Expression>
+                <Test a="2" message.a="richtig"/>
+            </Validator>
+        </Task>
+	</Chapter>
+</Lecture>`)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "expected 'richtig' at 'a', got 'falsch'")
+	}
+}
@@ -0,0 +1,120 @@
+package data
+
+import (
+	"time"
+)
+
+// ChapterSchedule contains the time dependent settings of a chapter.
+// All times are unix times, zero means that the time is not set.
+type ChapterSchedule struct {
+	// VisibleFrom is the time the chapter becomes visible
+	VisibleFrom int64
+	// VisibleUntil is the time the chapter is hidden again
+	VisibleUntil int64
+	// SolutionsFrom is the time the solutions of the chapter are released
+	SolutionsFrom int64
+}
+
+// IsVisible returns true if the chapter is visible at the given time
+func (cs ChapterSchedule) IsVisible(now time.Time) bool {
+	t := now.Unix()
+	if cs.VisibleFrom > 0 && t < cs.VisibleFrom {
+		return false
+	}
+	if cs.VisibleUntil > 0 && t >= cs.VisibleUntil {
+		return false
+	}
+	return true
+}
+
+// SolutionsReleased returns true if the solutions are released at the given time
+func (cs ChapterSchedule) SolutionsReleased(now time.Time) bool {
+	return cs.SolutionsFrom > 0 && now.Unix() >= cs.SolutionsFrom
+}
+
+// StateId returns the id used to store the settings and the user data
+// of the chapter. If the chapter has an id, it is used, so that the data
+// stays valid if the chapters are reordered.
+func (c *Chapter) StateId() string {
+	if c.Id != "" {
+		return c.Id
+	}
+	return c.num.String()
+}
+
+// MigrateStateIds moves the entries stored using the number of a chapter to
+// the id of the chapter. This is necessary if an id is added to an existing
+// chapter. Returns true if the map was modified.
+func MigrateStateIds[V any](l *Lecture, m map[string]V) bool {
+	modified := false
+	for _, c := range l.AllChapters() {
+		if c.Id == "" {
+			continue
+		}
+		if v, ok := m[c.num.String()]; ok {
+			if _, exists := m[c.Id]; !exists {
+				m[c.Id] = v
+			}
+			delete(m, c.num.String())
+			modified = true
+		}
+	}
+	return modified
+}
+
+// AllChapters returns all chapters of the lecture including the sub chapters
+func (l *Lecture) AllChapters() []*Chapter {
+	var chapters []*Chapter
+	var collect func(list ChapterList)
+	collect = func(list ChapterList) {
+		for _, c := range list {
+			chapters = append(chapters, c)
+			collect(c.Chapter)
+		}
+	}
+	collect(l.Chapter)
+	return chapters
+}
+
+// Schedule returns the schedule of the given chapter
+func (ls LectureState) Schedule(c *Chapter) ChapterSchedule {
+	return ls.Schedules[c.StateId()]
+}
+
+// SetSchedule sets the schedule of the given chapter
+func (ls *LectureState) SetSchedule(c *Chapter, cs ChapterSchedule) {
+	if ls.Schedules == nil {
+		ls.Schedules = map[string]ChapterSchedule{}
+	}
+	if cs == (ChapterSchedule{}) {
+		delete(ls.Schedules, c.StateId())
+	} else {
+		ls.Schedules[c.StateId()] = cs
+	}
+}
+
+// IsVisible returns true if the chapter and all its parent chapters
+// are visible at the given time.
+func (ls LectureState) IsVisible(c *Chapter, now time.Time) bool {
+	for ; c != nil; c = c.ParentChapter {
+		if !ls.Schedule(c).IsVisible(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// SolutionsReleased returns true if the solutions of the chapter can be
+// shown at the given time. This is the case if the solutions are enabled
+// for the whole lecture or released for the chapter or one of its parents.
+func (ls LectureState) SolutionsReleased(c *Chapter, now time.Time) bool {
+	if ls.ShowSolutions {
+		return true
+	}
+	for ; c != nil; c = c.ParentChapter {
+		if ls.Schedule(c).SolutionsReleased(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package data
+
+import (
+	"bytes"
+	"github.com/hneemann/objectDB/serialize"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChapterSchedule(t *testing.T) {
+	lecture, err := readLectureToTest(examLecture("", ""))
+	assert.NoError(t, err)
+	parent := lecture.Chapter[0]
+	sub := parent.Chapter[0]
+
+	now := time.Unix(1000, 0)
+	var state LectureState
+	assert.True(t, state.IsVisible(sub, now))
+	assert.False(t, state.SolutionsReleased(sub, now))
+
+	state.SetSchedule(parent, ChapterSchedule{VisibleFrom: 1001})
+	assert.False(t, state.IsVisible(parent, now))
+	assert.False(t, state.IsVisible(sub, now), "hidden parent hides sub chapter")
+	assert.True(t, state.IsVisible(sub, time.Unix(1001, 0)))
+
+	state.SetSchedule(parent, ChapterSchedule{VisibleUntil: 1000, SolutionsFrom: 900})
+	assert.False(t, state.IsVisible(sub, now))
+	assert.True(t, state.IsVisible(sub, time.Unix(999, 0)))
+	assert.True(t, state.SolutionsReleased(sub, now), "released at parent")
+	assert.False(t, state.SolutionsReleased(sub, time.Unix(899, 0)))
+
+	state.SetSchedule(parent, ChapterSchedule{})
+	assert.Empty(t, state.Schedules)
+
+	state.ShowSolutions = true
+	assert.True(t, state.SolutionsReleased(sub, now))
+}
+
+func TestLegacyLectureStates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	var b bytes.Buffer
+	err := serialize.New().Write(&b, map[LectureId]legacyLectureState{
+		"ET1": {ShowSolutions: true},
+		"ET2": {Disabled: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, b.Bytes(), 0644))
+
+	states := NewLectureStates(path)
+	assert.Equal(t, LectureState{ShowSolutions: true}, states.Get("ET1"))
+	assert.Equal(t, LectureState{Disabled: true}, states.Get("ET2"))
+}
+
+func TestLectureStatesMissingFields(t *testing.T) {
+	// a state written by a version which knows only the first fields
+	type oldState struct {
+		ShowSolutions bool
+		ShowAllTasks  bool
+		Disabled      bool
+		ShowWholePool bool
+		PoolRound     int
+	}
+	var s bytes.Buffer
+	assert.NoError(t, serialize.New().Write(&s, oldState{ShowSolutions: true, PoolRound: 2}))
+
+	path := filepath.Join(t.TempDir(), "state")
+	var b bytes.Buffer
+	err := serialize.New().Write(&b, map[LectureId][]byte{"ET1": s.Bytes(), "ET2": s.Bytes()})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, b.Bytes(), 0644))
+
+	states := NewLectureStates(path)
+	assert.Equal(t, LectureState{ShowSolutions: true, PoolRound: 2}, states.Get("ET1"))
+	assert.Equal(t, LectureState{ShowSolutions: true, PoolRound: 2}, states.Get("ET2"))
+
+	assert.NoError(t, states.SetState("ET2", LectureState{Disabled: true, Exams: map[string]ExamState{"0": {Start: 5}}, Schedules: map[string]ChapterSchedule{"0": {VisibleFrom: 7}}}))
+	read := NewLectureStates(path)
+	assert.True(t, read.Get("ET1").ShowSolutions)
+	assert.Equal(t, 2, read.Get("ET1").PoolRound)
+	assert.Equal(t, LectureState{Disabled: true, Exams: map[string]ExamState{"0": {Start: 5}}, Schedules: map[string]ChapterSchedule{"0": {VisibleFrom: 7}}}, read.Get("ET2"))
+}
+
+func TestMigrateStateIds(t *testing.T) {
+	lecture, err := readLectureToTest(examLecture(`id="exam" examMinutes="30"`, ""))
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "state")
+	states := NewLectureStates(path)
+	assert.NoError(t, states.SetState("ET1", LectureState{
+		Exams:     map[string]ExamState{"0.0": {Start: 5}},
+		Schedules: map[string]ChapterSchedule{"0": {VisibleFrom: 7}, "0.0": {VisibleFrom: 9}},
+	}))
+
+	lectures := &Lectures{}
+	lectures.Insert(lecture)
+	states.Migrate(lectures)
+
+	expected := LectureState{
+		Exams:     map[string]ExamState{"exam": {Start: 5}},
+		Schedules: map[string]ChapterSchedule{"0": {VisibleFrom: 7}, "exam": {VisibleFrom: 9}},
+	}
+	assert.Equal(t, expected, states.Get("ET1"))
+	assert.Equal(t, expected, NewLectureStates(path).Get("ET1"), "migration is persisted")
+
+	exam := lecture.Chapter[0].Chapter[0]
+	assert.Equal(t, ExamState{Start: 5}, states.Get("ET1").Exam(exam))
+}
@@ -4,14 +4,83 @@ import (
 	"bytes"
 	"github.com/hneemann/objectDB/serialize"
 	"log"
+	"maps"
 	"os"
 	"sync"
 )
 
+// LectureState contains the settings of a lecture.
+// New fields are only appended at the end, see UnmarshalBinary.
 type LectureState struct {
 	ShowSolutions bool
 	ShowAllTasks  bool
 	Disabled      bool
+	// ShowWholePool makes all tasks of the pools visible
+	ShowWholePool bool
+	// PoolRound is incremented to draw new tasks from the pools
+	PoolRound int
+	// Exams contains the exam settings, the key is the state id of the chapter
+	Exams map[string]ExamState
+	// Schedules contains the chapter schedules, the key is the state id of the chapter
+	Schedules map[string]ChapterSchedule
+}
+
+// Exam returns the exam settings of the given chapter
+func (ls LectureState) Exam(c *Chapter) ExamState {
+	return ls.Exams[c.StateId()]
+}
+
+// SetExam sets the exam settings of the given chapter
+func (ls *LectureState) SetExam(c *Chapter, e ExamState) {
+	if ls.Exams == nil {
+		ls.Exams = map[string]ExamState{}
+	}
+	ls.Exams[c.StateId()] = e
+}
+
+// lectureStateData is used to serialize the LectureState without
+// calling the MarshalBinary method recursively
+type lectureStateData LectureState
+
+// MarshalBinary serializes each state on its own, so that a state written
+// by an older version can be read up to the missing fields.
+func (ls LectureState) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	err := serialize.New().Write(&b, lectureStateData(ls))
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary reads a state. If the data ends before all fields are
+// read, the missing fields keep their zero value.
+func (ls *LectureState) UnmarshalBinary(data []byte) error {
+	var d lectureStateData
+	r := bytes.NewReader(data)
+	err := serialize.New().Read(r, &d)
+	if err != nil && r.Len() > 0 {
+		return err
+	}
+	*ls = LectureState(d)
+	return nil
+}
+
+// legacyLectureState is the state written by versions without pools
+type legacyLectureState struct {
+	ShowSolutions bool
+	ShowAllTasks  bool
+	Disabled      bool
+}
+
+// readLegacy reads the states written by versions without pools
+func readLegacy(fileData []byte) (map[LectureId]LectureState, bool) {
+	var legacy map[LectureId]legacyLectureState
+	if serialize.New().Read(bytes.NewReader(fileData), &legacy) != nil {
+		return nil, false
+	}
+	states := make(map[LectureId]LectureState)
+	for id, s := range legacy {
+		states[id] = LectureState{ShowSolutions: s.ShowSolutions, ShowAllTasks: s.ShowAllTasks, Disabled: s.Disabled}
+	}
+	return states, true
 }
 
 type LectureStates struct {
@@ -29,7 +98,12 @@ func NewLectureStates(path string) *LectureStates {
 	}
 	err = serialize.New().Read(bytes.NewReader(fileData), &(ls.states))
 	if err != nil {
-		log.Print("could not deserialize state", path)
+		if states, ok := readLegacy(fileData); ok {
+			ls.states = states
+		} else {
+			ls.states = make(map[LectureId]LectureState)
+			log.Print("could not deserialize state", path)
+		}
 	}
 
 	return &ls
@@ -50,9 +124,38 @@ func (ls *LectureStates) Get(id LectureId) LectureState {
 	defer ls.mutex.Unlock()
 
 	state := ls.states[id]
+	// the maps are copied, so the caller can modify the returned state
+	state.Exams = maps.Clone(state.Exams)
+	state.Schedules = maps.Clone(state.Schedules)
 	return state
 }
 
+// Migrate moves the settings stored using the number of a chapter to the
+// id of the chapter, if an id was added to the chapter.
+func (ls *LectureStates) Migrate(lectures *Lectures) {
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+
+	modified := false
+	for _, lec := range lectures.List() {
+		state, ok := ls.states[lec.Id]
+		if !ok {
+			continue
+		}
+		if MigrateStateIds(lec, state.Exams) {
+			modified = true
+		}
+		if MigrateStateIds(lec, state.Schedules) {
+			modified = true
+		}
+	}
+	if modified {
+		if err := ls.persist(); err != nil {
+			log.Println("could not persist migrated state", err)
+		}
+	}
+}
+
 func (ls *LectureStates) SetState(id LectureId, state LectureState) error {
 	ls.mutex.Lock()
 	defer ls.mutex.Unlock()
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagCount is a tag together with the number of tasks using it
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// initTags checks the tags of the task
+func (t *Task) initTags() error {
+	for i, tag := range t.Tag {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return t.pos.errorf("empty tag in chapter '%s' task '%s'", t.chapter.Title, t.Name)
+		}
+		if strings.Contains(tag, "/") {
+			return t.pos.errorf("tag '%s' in chapter '%s' task '%s' contains a '/'", tag, t.chapter.Title, t.Name)
+		}
+		t.Tag[i] = tag
+	}
+	return nil
+}
+
+// HasTag returns true if the task has the given tag
+func (t *Task) HasTag(tag string) bool {
+	for _, tt := range t.Tag {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// initTags collects the tags of all tasks
+func (l *Lecture) initTags() {
+	l.tags = map[string][]*Task{}
+	for task := range l.Iter {
+		for _, tag := range task.Tag {
+			tasks := l.tags[tag]
+			if len(tasks) == 0 || tasks[len(tasks)-1] != task {
+				l.tags[tag] = append(tasks, task)
+			}
+		}
+	}
+}
+
+// Tags returns all tags used in the lecture sorted by name
+func (l *Lecture) Tags() []TagCount {
+	var tags []TagCount
+	for tag, tasks := range l.tags {
+		tags = append(tags, TagCount{Tag: tag, Count: len(tasks)})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i].Tag) < strings.ToLower(tags[j].Tag)
+	})
+	return tags
+}
+
+// TaggedTasks returns all tasks with the given tag in the order of the lecture
+func (l *Lecture) TaggedTasks(tag string) []*Task {
+	return l.tags[tag]
+}
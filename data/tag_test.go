@@ -0,0 +1,63 @@
+package data
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func tagLecture(tags1, tags2 string) string {
+	return fmt.Sprintf(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task>
+            <Name>A</Name>%s
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator><Expression>cmpValues(1,answer.a,1)</Expression></Validator>
+            </Input>
+        </Task>
+	</Chapter>
+    <Chapter>
+        <Title>Netzwerke</Title>
+        <Task>
+            <Name>B</Name>%s
+            <Input id="a" type="number">
+                <Label>a:</Label>
+                <Validator><Expression>cmpValues(2,answer.a,1)</Expression></Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`, tags1, tags2)
+}
+
+func TestTags(t *testing.T) {
+	lecture, err := readLectureToTest(tagLecture(
+		"<Tag> Kirchhoff </Tag><Tag>Ohm</Tag><Tag>Ohm</Tag>",
+		"<Tag>Kirchhoff</Tag>"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []TagCount{{"Kirchhoff", 2}, {"Ohm", 1}}, lecture.Tags())
+
+	a := lecture.Chapter[0].Task[0]
+	b := lecture.Chapter[1].Task[0]
+	assert.Equal(t, []*Task{a, b}, lecture.TaggedTasks("Kirchhoff"))
+	assert.Equal(t, []*Task{a}, lecture.TaggedTasks("Ohm"))
+	assert.Nil(t, lecture.TaggedTasks("Thevenin"))
+	assert.True(t, a.HasTag("Kirchhoff"))
+	assert.False(t, b.HasTag("Ohm"))
+}
+
+func TestTagsInit(t *testing.T) {
+	_, err := readLectureToTest(tagLecture("<Tag> </Tag>", ""))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "empty tag")
+	}
+	_, err = readLectureToTest(tagLecture("", "<Tag>a/b</Tag>"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "tag 'a/b'")
+	}
+}
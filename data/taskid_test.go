@@ -0,0 +1,134 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func taskIdLecture(tasks string) string {
+	return `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>` + tasks + `
+	</Chapter>
+</Lecture>`
+}
+
+// taskIdChapters creates a lecture with two chapters containing
+// one task each, so that both tasks have the same content hash.
+func taskIdChapters(task1, task2 string) string {
+	return `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>` + task1 + `
+	</Chapter>
+    <Chapter>
+        <Title>Wechselstromkreise</Title>` + task2 + `
+	</Chapter>
+</Lecture>`
+}
+
+func taskIdTask(attr, oldIds string) string {
+	return `
+        <Task` + attr + `>` + oldIds + `
+            <Question>Frage</Question>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>cmpValues(40,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>`
+}
+
+func TestTaskIdMigration(t *testing.T) {
+	old, err := readLectureToTest(taskIdLecture(taskIdTask("", "")))
+	assert.NoError(t, err)
+	oldId := old.Chapter[0].Task[0].TID()
+
+	lecture, err := readLectureToTest(taskIdLecture(taskIdTask(` id="uq"`, "<OldId>first</OldId><OldId>second</OldId>")))
+	assert.NoError(t, err)
+	assert.Equal(t, TaskId("uq"), lecture.Chapter[0].Task[0].TID())
+	assert.True(t, lecture.HasTask("uq"))
+
+	for _, tid := range []TaskId{oldId, "first", "second"} {
+		newId, ok := lecture.MigrateId(tid)
+		assert.True(t, ok)
+		assert.Equal(t, TaskId("uq"), newId)
+	}
+	_, ok := lecture.MigrateId("unknown")
+	assert.False(t, ok)
+}
+
+func TestTaskIdMigrationAmbiguous(t *testing.T) {
+	old, err := readLectureToTest(taskIdLecture(taskIdTask("", "")))
+	assert.NoError(t, err)
+	oldId := old.Chapter[0].Task[0].TID()
+
+	lecture, err := readLectureToTest(taskIdChapters(taskIdTask(` id="a"`, ""), taskIdTask(` id="b"`, "")))
+	assert.NoError(t, err)
+	_, ok := lecture.MigrateId(oldId)
+	assert.False(t, ok)
+
+	lecture, err = readLectureToTest(taskIdChapters(taskIdTask("", ""), taskIdTask(` id="a"`, "")))
+	assert.NoError(t, err)
+	_, ok = lecture.MigrateId(oldId)
+	assert.False(t, ok)
+	assert.True(t, lecture.HasTask(oldId))
+}
+
+func TestTaskIdInit(t *testing.T) {
+	tests := []struct {
+		name          string
+		tasks         string
+		expectedError string
+	}{
+		{"ok", taskIdTask("", "") + taskIdTask(` id="a"`, ""), ""},
+		{"sameContent", taskIdTask("", "") + taskIdTask("", ""), ""},
+		{"duplicate", taskIdTask(` id="a"`, "") + taskIdTask(` id="a"`, ""), "has the same id"},
+		{"invalid", taskIdTask(` id="1a"`, ""), "invalid id '1a'"},
+		{"oldIdInUse", taskIdTask(` id="a"`, "") + taskIdTask(` id="b"`, "<OldId>a</OldId>"), "is used by an existing task"},
+		{"oldIdTwice", taskIdTask(` id="a"`, "<OldId>c</OldId>") + taskIdTask(` id="b"`, "<OldId>c</OldId>"), "is used multiple times"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(taskIdLecture(tt.tasks))
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskIdSameContent(t *testing.T) {
+	tests := []struct {
+		name          string
+		task1, task2  string
+		expectedError string
+	}{
+		{"noIds", taskIdTask("", ""), taskIdTask("", ""), ""},
+		{"twoIds", taskIdTask(` id="a"`, ""), taskIdTask(` id="b"`, ""), ""},
+		{"oneId", taskIdTask("", ""), taskIdTask(` id="a"`, ""), ""},
+		{"sameId", taskIdTask(` id="a"`, ""), taskIdTask(` id="a"`, ""), "has the same id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(taskIdChapters(tt.task1, tt.task2))
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.expectedError)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TaskTemplate is a task containing placeholders like {{R1}}.
+// The placeholders are replaced by the values given in a UseTemplate
+// element. The content of the template is the content of a task.
+type TaskTemplate struct {
+	Name    string `xml:"name,attr"`
+	Content string `xml:",innerxml"`
+	pos     position
+}
+
+// UseTemplate creates a task from a template. All attributes besides
+// name and id are the values of the placeholders.
+type UseTemplate struct {
+	Name   string
+	Id     TaskId
+	Values map[string]string
+	pos    position
+}
+
+func (u *UseTemplate) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	u.pos = newPosition(d)
+	u.Values = map[string]string{}
+	for _, a := range start.Attr {
+		switch a.Name.Local {
+		case "name":
+			u.Name = a.Value
+		case "id":
+			u.Id = TaskId(a.Value)
+		default:
+			u.Values[a.Name.Local] = a.Value
+		}
+	}
+	return d.Skip()
+}
+
+var placeholder = regexp.MustCompile(`{{([a-zA-Z][a-zA-Z0-9_]*)}}`)
+
+// expand creates a task from the template.
+// If no id is given in the instance, the id is created from the name of
+// the template and the placeholder values. This way the id stays stable
+// if other tasks are added or the template is modified.
+func (tt *TaskTemplate) expand(u *UseTemplate) (*Task, error) {
+	used := map[string]bool{}
+	var err error
+	content := placeholder.ReplaceAllStringFunc(tt.Content, func(s string) string {
+		name := s[2 : len(s)-2]
+		v, ok := u.Values[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("no value given for placeholder '%s'", name)
+			}
+			return s
+		}
+		used[name] = true
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(v))
+		return b.String()
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name := range u.Values {
+		if !used[name] {
+			return nil, fmt.Errorf("placeholder '%s' is not used in the template", name)
+		}
+	}
+
+	// the line breaks keep the line numbers in error messages valid
+	text := strings.Repeat("\n", max(tt.pos.line-1, 0)) + "<Task>" + content + "</Task>"
+	var task Task
+	err = xml.NewDecoder(strings.NewReader(text)).Decode(&task)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+	task.setFile(tt.pos.file)
+	task.pos = u.pos
+	task.template = u
+	if u.Id != "" {
+		task.Id = u.Id
+	} else {
+		task.Id = TaskId(tt.Name + "_" + u.hash())
+	}
+	return &task, nil
+}
+
+// hash returns a hash of the placeholder values
+func (u *UseTemplate) hash() string {
+	var names []string
+	for n := range u.Values {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	h := sha1.New()
+	for _, n := range names {
+		h.Write([]byte(n))
+		h.Write([]byte{0})
+		h.Write([]byte(u.Values[n]))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:10]
+}
+
+type templates map[string]*TaskTemplate
+
+// collectTemplates returns all templates defined in the chapters
+func (c ChapterList) collectTemplates(t templates) error {
+	for _, ch := range c {
+		if err := t.add(ch.TaskTemplate); err != nil {
+			return err
+		}
+		if err := ch.Chapter.collectTemplates(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t templates) add(list []*TaskTemplate) error {
+	for _, tt := range list {
+		if err := checkIdent(tt.Name); err != nil {
+			return tt.pos.errorf("invalid template name '%s': %w", tt.Name, err)
+		}
+		if _, ok := t[tt.Name]; ok {
+			return tt.pos.errorf("template '%s' is defined twice", tt.Name)
+		}
+		t[tt.Name] = tt
+	}
+	return nil
+}
+
+// sourcePos returns the position of the xml element the task was created from.
+// The tasks of a pool are located at the position of the pool.
+func (t *Task) sourcePos() position {
+	if t.pool != nil {
+		return t.pool.pos
+	}
+	if t.template != nil {
+		return t.template.pos
+	}
+	return t.pos
+}
+
+// sortBySource sorts the tasks in the order they appear in the xml file
+func sortBySource(tasks []*Task) {
+	slices.SortStableFunc(tasks, func(a, b *Task) int {
+		pa, pb := a.sourcePos(), b.sourcePos()
+		if pa.before(pb) {
+			return -1
+		}
+		if pb.before(pa) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// expandTemplates creates the tasks defined by UseTemplate elements.
+// The created tasks are placed at the position of the UseTemplate element.
+func (c ChapterList) expandTemplates(t templates) error {
+	for _, ch := range c {
+		tasks, err := t.expand(ch.UseTemplate)
+		if err != nil {
+			return err
+		}
+		ch.Task = append(ch.Task, tasks...)
+		sortBySource(ch.Task)
+		for _, p := range ch.Pool {
+			tasks, err = t.expand(p.UseTemplate)
+			if err != nil {
+				return err
+			}
+			p.Task = append(p.Task, tasks...)
+			sortBySource(p.Task)
+		}
+		if err := ch.Chapter.expandTemplates(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t templates) expand(list []*UseTemplate) ([]*Task, error) {
+	var tasks []*Task
+	for _, u := range list {
+		tt, ok := t[u.Name]
+		if !ok {
+			return nil, u.pos.errorf("template '%s' not found", u.Name)
+		}
+		task, err := tt.expand(u)
+		if err != nil {
+			return nil, u.pos.errorf("error expanding template '%s' defined at %s: %w", u.Name, tt.pos, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
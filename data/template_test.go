@@ -0,0 +1,129 @@
+package data
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func templateLecture(template, use string) string {
+	return fmt.Sprintf(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <TaskTemplate name="ohm">%s</TaskTemplate>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        %s
+	</Chapter>
+</Lecture>`, template, use)
+}
+
+const ohmTemplate = `
+        <Name>Ohm {{R}}</Name>
+        <Question>Berechnen Sie den Strom an 10V und {{R}}Ω.</Question>
+        <Input id="I" type="number">
+            <Label>I:</Label>
+            <Validator>
+                <Expression>cmpValues(10/{{R}},answer.I,1)</Expression>
+            </Validator>
+        </Input>`
+
+func TestTemplate(t *testing.T) {
+	lecture, err := readLectureToTest(templateLecture(ohmTemplate, `
+        <UseTemplate name="ohm" R="100"/>
+        <UseTemplate name="ohm" R="1000" id="ohm1k"/>`))
+	assert.NoError(t, err)
+	tasks := lecture.Chapter[0].Task
+	assert.Equal(t, 2, len(tasks))
+
+	assert.Contains(t, tasks[0].Name, "Ohm 100")
+	assert.Equal(t, "Berechnen Sie den Strom an 10V und 100Ω.", tasks[0].Question)
+	assert.Equal(t, 1.0, tasks[0].Score(DataMap{"I": "0.1"}, nil, false).Score)
+	assert.Equal(t, 0.0, tasks[0].Score(DataMap{"I": "0.01"}, nil, false).Score)
+	assert.Equal(t, 1.0, tasks[1].Score(DataMap{"I": "0.01"}, nil, false).Score)
+
+	assert.Equal(t, TaskId("ohm1k"), tasks[1].Id)
+
+	// the id depends only on the template name and the values
+	other, err := readLectureToTest(templateLecture(ohmTemplate, `
+        <UseTemplate name="ohm" R="47"/>
+        <UseTemplate name="ohm" R="100"/>`))
+	assert.NoError(t, err)
+	assert.Equal(t, tasks[0].Id, other.Chapter[0].Task[1].Id)
+	assert.NotEqual(t, tasks[0].Id, other.Chapter[0].Task[0].Id)
+}
+
+func TestTemplateOrder(t *testing.T) {
+	lecture, err := readLectureToTest(templateLecture(ohmTemplate, `
+        <Task>
+            <Name>first</Name>
+            <Question>Frage</Question>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+        <UseTemplate name="ohm" R="100"/>
+        <Task>
+            <Name>last</Name>
+            <Question>Frage</Question>
+            <Input id="a" type="text">
+                <Label>a:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.a,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>`))
+	assert.NoError(t, err)
+	tasks := lecture.Chapter[0].Task
+	if assert.Equal(t, 3, len(tasks)) {
+		assert.Equal(t, "Frage 1: first", tasks[0].Name)
+		assert.Equal(t, "Frage 2: Ohm 100", tasks[1].Name)
+		assert.Equal(t, "Frage 3: last", tasks[2].Name)
+	}
+}
+
+func TestTemplatePool(t *testing.T) {
+	lecture, err := readLectureToTest(templateLecture(ohmTemplate, `
+        <Pool draw="1">
+            <UseTemplate name="ohm" R="100"/>
+            <UseTemplate name="ohm" R="200"/>
+        </Pool>`))
+	assert.NoError(t, err)
+	chapter := lecture.Chapter[0]
+	assert.Equal(t, 2, len(chapter.Task))
+	assert.Equal(t, 1, chapter.Tasks(Selection{Seed: "a"}))
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		use      string
+		err      []string
+	}{
+		{"unknown", ohmTemplate, `<UseTemplate name="unknown" R="100"/>`,
+			[]string{"template 'unknown' not found"}},
+		{"missing", ohmTemplate, `<UseTemplate name="ohm"/>`,
+			[]string{"template 'ohm'", "no value given for placeholder 'R'"}},
+		{"unused", ohmTemplate, `<UseTemplate name="ohm" R="100" U="10"/>`,
+			[]string{"template 'ohm'", "placeholder 'U' is not used"}},
+		{"invalid", ohmTemplate, `<UseTemplate name="ohm" R="a+"/>`,
+			[]string{"template 'ohm'", "xml:11:"}},
+		{"twice", ohmTemplate, `<UseTemplate name="ohm" R="100"/><UseTemplate name="ohm" R="100"/>`,
+			[]string{"has the same id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLectureToTest(templateLecture(tt.template, tt.use))
+			if assert.Error(t, err) {
+				for _, e := range tt.err {
+					assert.Contains(t, err.Error(), e)
+				}
+			}
+		})
+	}
+}
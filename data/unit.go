@@ -0,0 +1,159 @@
+package data
+
+import (
+	"fmt"
+	"github.com/hneemann/parser2/funcGen"
+	"github.com/hneemann/parser2/value"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// unit is a physical unit
+type unit struct {
+	symbol string
+	name   string
+}
+
+var (
+	unitVolt    = &unit{symbol: "V", name: "eine Spannung"}
+	unitAmpere  = &unit{symbol: "A", name: "ein Strom"}
+	unitOhm     = &unit{symbol: "Ω", name: "ein Widerstand"}
+	unitWatt    = &unit{symbol: "W", name: "eine Leistung"}
+	unitFarad   = &unit{symbol: "F", name: "eine Kapazität"}
+	unitHenry   = &unit{symbol: "H", name: "eine Induktivität"}
+	unitSecond  = &unit{symbol: "s", name: "eine Zeit"}
+	unitHertz   = &unit{symbol: "Hz", name: "eine Frequenz"}
+	unitJoule   = &unit{symbol: "J", name: "eine Energie"}
+	unitCoulomb = &unit{symbol: "C", name: "eine Ladung"}
+	unitSiemens = &unit{symbol: "S", name: "ein Leitwert"}
+	unitTesla   = &unit{symbol: "T", name: "eine magnetische Flussdichte"}
+	unitWeber   = &unit{symbol: "Wb", name: "ein magnetischer Fluss"}
+	unitKelvin  = &unit{symbol: "K", name: "eine Temperatur"}
+)
+
+// units maps all accepted unit symbols to the units
+var units = map[string]*unit{
+	"V":   unitVolt,
+	"A":   unitAmpere,
+	"Ω":   unitOhm,
+	"Ohm": unitOhm,
+	"ohm": unitOhm,
+	"W":   unitWatt,
+	"F":   unitFarad,
+	"H":   unitHenry,
+	"s":   unitSecond,
+	"Hz":  unitHertz,
+	"J":   unitJoule,
+	"C":   unitCoulomb,
+	"S":   unitSiemens,
+	"T":   unitTesla,
+	"Wb":  unitWeber,
+	"K":   unitKelvin,
+}
+
+var prefixes = map[string]float64{
+	"p": 1e-12,
+	"n": 1e-9,
+	"µ": 1e-6, // micro sign
+	"μ": 1e-6, // greek mu
+	"u": 1e-6,
+	"m": 1e-3,
+	"k": 1e3,
+	"M": 1e6,
+	"G": 1e9,
+}
+
+// parseUnit parses a unit symbol with an optional prefix.
+// A prefix without a unit symbol is not accepted.
+func parseUnit(s string) (float64, *unit, bool) {
+	if u, ok := units[s]; ok {
+		return 1, u, true
+	}
+	for p, f := range prefixes {
+		if rest, found := strings.CutPrefix(s, p); found {
+			if u, ok := units[rest]; ok {
+				return f, u, true
+			}
+		}
+	}
+	return 0, nil, false
+}
+
+// splitUnit splits a trailing unit from the given expression.
+// If there is no unit, the expression is returned unchanged and the
+// returned unit string is empty.
+func splitUnit(expr string) (string, string, float64, *unit) {
+	trimmed := strings.TrimRightFunc(expr, unicode.IsSpace)
+	runes := []rune(trimmed)
+	i := len(runes)
+	for i > 0 && unicode.IsLetter(runes[i-1]) {
+		i--
+	}
+	if i == 0 || i == len(runes) {
+		return expr, "", 1, nil
+	}
+	if before := runes[i-1]; !(unicode.IsDigit(before) || unicode.IsSpace(before) || before == ')' || before == '.') {
+		return expr, "", 1, nil
+	}
+
+	unitStr := string(runes[i:])
+	factor, u, ok := parseUnit(unitStr)
+	if !ok {
+		return expr, "", 1, nil
+	}
+	return strings.TrimRightFunc(string(runes[:i]), unicode.IsSpace), unitStr, factor, u
+}
+
+// cmpQuantity compares a physical quantity given by the user with the expected value.
+// The expected value is given in the expected unit, which may contain a prefix.
+func cmpQuantity(stack funcGen.Stack[value.Value], _ []value.Value) (value.Value, error) {
+	expected, ok := stack.Get(0).ToFloat()
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %v", stack.Get(0))
+	}
+	isStr, ok := stack.Get(1).(value.String)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %v", stack.Get(1))
+	}
+	unitStr, ok := stack.Get(2).(value.String)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %v", stack.Get(2))
+	}
+	percent, ok := stack.Get(3).ToFloat()
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %v", stack.Get(3))
+	}
+
+	factor, expUnit, ok := parseUnit(string(unitStr))
+	if !ok {
+		return nil, fmt.Errorf("invalid unit '%s'", unitStr)
+	}
+	expected *= factor
+
+	if isStr == "" {
+		return nil, GuiError{message: "Die Eingabe ist leer!"}
+	}
+	number, isUnitStr, isFactor, isUnit := splitUnit(string(isStr))
+	if isUnit == nil {
+		return value.String(fmt.Sprintf("Es fehlt die Einheit! Gesucht ist %s in %s.", expUnit.name, expUnit.symbol)), nil
+	}
+	if isUnit != expUnit {
+		return value.String(fmt.Sprintf("Die Einheit '%s' passt nicht! Gesucht ist %s in %s.", isUnitStr, expUnit.name, expUnit.symbol)), nil
+	}
+
+	v, err := createExpression(number, nil)
+	if err != nil {
+		return nil, err
+	}
+	is, err := v.(Expression).eval()
+	if err != nil {
+		return nil, err
+	}
+	is *= isFactor
+
+	if expected == 0 {
+		return value.Bool(math.Abs(is) < percent/100), nil
+	}
+	return value.Bool(math.Abs((is-expected)/expected*100) < percent), nil
+}
@@ -0,0 +1,87 @@
+package data
+
+import (
+	"github.com/hneemann/parser2/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_splitUnit(t *testing.T) {
+	tests := []struct {
+		expr    string
+		number  string
+		unitStr string
+		factor  float64
+		unit    *unit
+	}{
+		{"4.7 kΩ", "4.7", "kΩ", 1e3, unitOhm},
+		{"4.7kOhm", "4.7", "kOhm", 1e3, unitOhm},
+		{"12mV", "12", "mV", 1e-3, unitVolt},
+		{"12 V ", "12", "V", 1, unitVolt},
+		{"3µA", "3", "µA", 1e-6, unitAmpere},
+		{"(1+2) mA", "(1+2)", "mA", 1e-3, unitAmpere},
+		{"2 ms", "2", "ms", 1e-3, unitSecond},
+		{"50Hz", "50", "Hz", 1, unitHertz},
+		{"4.7k", "4.7k", "", 1, nil},
+		{"5 m", "5 m", "", 1, nil},
+		{"12", "12", "", 1, nil},
+		{"2*pi", "2*pi", "", 1, nil},
+		{"2 pi", "2 pi", "", 1, nil},
+		{"1e3", "1e3", "", 1, nil},
+		{"V", "V", "", 1, nil},
+		{"2*V", "2*V", "", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			number, unitStr, factor, u := splitUnit(tt.expr)
+			assert.Equal(t, tt.number, number)
+			assert.Equal(t, tt.unitStr, unitStr)
+			assert.InDelta(t, tt.factor, factor, 1e-15)
+			assert.Equal(t, tt.unit, u)
+		})
+	}
+}
+
+func TestCmpQuantity(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result value.Value
+	}{
+		{"cmpQuantity(4700,\"4.7 kΩ\",\"Ω\",1)", value.Bool(true)},
+		{"cmpQuantity(4.7,\"4700 Ohm\",\"kΩ\",1)", value.Bool(true)},
+		{"cmpQuantity(0.012,\"12 mV\",\"V\",1)", value.Bool(true)},
+		{"cmpQuantity(0.012,\"12 V\",\"V\",1)", value.Bool(false)},
+		{"cmpQuantity(0.012,\"12 mA\",\"V\",1)", value.String("Die Einheit 'mA' passt nicht! Gesucht ist eine Spannung in V.")},
+		{"cmpQuantity(0.012,\"12m\",\"V\",1)", value.String("Es fehlt die Einheit! Gesucht ist eine Spannung in V.")},
+		{"cmpQuantity(2000,\"2 k\",\"V\",1)", value.String("Es fehlt die Einheit! Gesucht ist eine Spannung in V.")},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.expr, func(t *testing.T) {
+			f, err := myParser.Generate(tst.expr)
+			assert.NoError(t, err)
+			if f != nil {
+				r, err := f.Eval()
+				assert.NoError(t, err)
+				assert.Equal(t, tst.result, r)
+			}
+		})
+	}
+}
+
+func TestCmpValuesWithUnit(t *testing.T) {
+	for _, expr := range []string{
+		"cmpValues(4700,\"4.7 kV\",1)",
+		"cmpValues(0.005,\"5 m\",1)",
+		"cmpValuesAbs(40,\"40 V\",1)",
+		"num(\"2 k\")",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			f, err := myParser.Generate(expr)
+			if err == nil {
+				_, err = f.Eval()
+			}
+			assert.Error(t, err)
+		})
+	}
+}
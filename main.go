@@ -47,6 +47,10 @@ func Authenticate(user, pass string) (string, bool, error) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(check(os.Args[2:]))
+	}
+
 	dataFolder := flag.String("data", ".", "data folder")
 	logFolder := flag.String("logs", "logs", "log folder")
 	cert := flag.String("cert", "", "certificate file e.g. cert.pem")
@@ -70,6 +74,7 @@ func main() {
 	sessions := session.New(ensureFolderExists(filepath.Join(*dataFolder, "sessions")), lectures)
 
 	states := data.NewLectureStates(filepath.Join(*dataFolder, "state"))
+	states.Migrate(lectures)
 
 	mux := http.NewServeMux()
 
@@ -87,11 +92,14 @@ func main() {
 
 	mux.Handle("/static/", Cache(http.FileServer(http.FS(server.Static)), 60*8, *cache))
 	mux.Handle("/", sessions.Wrap(server.CreateMain(lectures, !isOidc, states)))
-	mux.Handle("/lecture/", CatchPanic(sessions.Wrap(server.CreateLecture(lectures))))
+	mux.Handle("/lecture/", CatchPanic(sessions.Wrap(server.CreateLecture(lectures, states))))
 	mux.Handle("/chapter/", CatchPanic(sessions.Wrap(server.CreateChapter(lectures, states))))
+	mux.Handle("/practice/", CatchPanic(sessions.Wrap(server.CreatePractice(lectures, states))))
+	mux.Handle("/review/", CatchPanic(sessions.Wrap(server.CreateReview(lectures, states))))
 	mux.Handle("/task/", CatchPanic(sessions.Wrap(server.CreateTask(lectures, states))))
-	mux.Handle("/admin/", CatchPanic(sessions.WrapAdmin(server.CreateAdmin(lectures))))
+	mux.Handle("/admin/", CatchPanic(sessions.WrapAdmin(server.CreateAdmin(lectures, states))))
 	mux.Handle("/statistics/", CatchPanic(sessions.WrapAdmin(server.CreateStatistics(lectures, sessions))))
+	mux.Handle("/exam/", CatchPanic(sessions.WrapAdmin(server.CreateExam(lectures, sessions, states))))
 	mux.Handle("/settings/", CatchPanic(sessions.WrapAdmin(server.CreateSettings(lectures, states))))
 	mux.Handle("/logs/", CatchPanic(sessions.WrapAdmin(server.CreateLogs(logPath))))
 	mux.Handle("/image/", CatchPanic(Cache(server.CreateImages(lectures), 60, *cache)))
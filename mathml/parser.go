@@ -288,6 +288,11 @@ type Table struct {
 	style []cellStyle
 }
 
+// NewTable creates a table with centered cells
+func NewTable(table [][]Ast) Ast {
+	return &Table{table: table}
+}
+
 func (t *Table) ToMathMl(w io.Writer, attr map[string]string) {
 	tag(w, "mtable", attr, func(w io.Writer) {
 		topLine := false
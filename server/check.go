@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/mathml"
+	"strings"
+)
+
+// Problem is a problem found while checking a lecture
+type Problem struct {
+	Where   string `json:"where"`
+	Message string `json:"message"`
+}
+
+// CheckMarkdown checks all markdown texts of the given lecture.
+// All LaTeX snippets are parsed and all referenced images need to
+// be contained in the lecture.
+func CheckMarkdown(lecture *data.Lecture) []Problem {
+	var problems []Problem
+	for where, md := range lecture.Markdown("check") {
+		if md == "" {
+			continue
+		}
+		ast.WalkFunc(parseMarkdown(md), func(node ast.Node, entering bool) ast.WalkStatus {
+			if !entering {
+				return ast.GoToNext
+			}
+			switch n := node.(type) {
+			case *ast.Math:
+				problems = checkLaTeX(problems, where, n.Literal)
+			case *ast.MathBlock:
+				problems = checkLaTeX(problems, where, n.Literal)
+			case *ast.Image:
+				name := string(n.Destination)
+				if !strings.Contains(name, "://") {
+					if _, err := lecture.GetFile(name); err != nil {
+						problems = append(problems, Problem{Where: where, Message: fmt.Sprintf("image '%s' not found", name)})
+					}
+				}
+			}
+			return ast.GoToNext
+		})
+	}
+	return problems
+}
+
+func checkLaTeX(problems []Problem, where string, latex []byte) []Problem {
+	_, err := mathml.ParseLaTeX(string(latex))
+	if err != nil {
+		return append(problems, Problem{Where: where, Message: fmt.Sprintf("%v in: %s", err, latex)})
+	}
+	return problems
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckMarkdown(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Description>Valid $a^2$ and ![Bild](https://example.com/a.png)</Description>
+    <Chapter>
+        <Title>Gleichstromkreise</Title>
+        <Task>
+            <Question>Broken $\frac{a}$ and ![Bild](missing.png)</Question>
+            <Input id="val1" type="text">
+                <Label>$U_Q/\u{V}$:</Label>
+                <Validator>
+                    <Expression>cmpValues(40,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	l, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	problems := CheckMarkdown(l)
+	if assert.Equal(t, 2, len(problems)) {
+		assert.Equal(t, "question of chapter 'Gleichstromkreise' task 'Frage 1'", problems[0].Where)
+		assert.Contains(t, problems[0].Message, "\\frac{a}")
+		assert.Equal(t, "image 'missing.png' not found", problems[1].Message)
+	}
+}
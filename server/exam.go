@@ -0,0 +1,256 @@
+package server
+
+import (
+	"fmt"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server/session"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const examTimeFormat = "02.01.2006 15:04"
+
+// examAnswers returns the answers stored in the exam
+func examAnswers(task *data.Task, e session.ExamAttempt) data.DataMap {
+	stored := e.Answers[task.TID()]
+	return readAnswers(task, func(i *data.Input) string {
+		return stored[i.Id]
+	})
+}
+
+// isResultVisible returns false if the task is part of an exam
+// whose results are not released yet.
+func isResultVisible(task *data.Task, state *data.LectureState, ses *session.Session) bool {
+	ch := task.Chapter()
+	if !ch.IsExam() || ses == nil || ses.IsAdmin() {
+		return true
+	}
+	return state.Exam(ch).Released
+}
+
+// examScore scores the answers given in an exam
+func examScore(task *data.Task, e session.ExamAttempt, seed string) (*data.Result, error) {
+	params, err := task.CreateParams(seed)
+	if err != nil {
+		return nil, err
+	}
+	return task.Score(examAnswers(task, e), params, false), nil
+}
+
+// finishExam submits the exam and stores the scores reached.
+// If the exam was already submitted, nothing happens.
+func finishExam(ch *data.Chapter, state *data.LectureState, ses *session.Session) {
+	if !ses.SubmitExam(ch) {
+		return
+	}
+
+	e := ses.Exam(ch)
+	for _, task := range ch.SelectedTasks(selection(state, ses)) {
+		res, err := examScore(task, e, ses.PersistToken())
+		if err != nil {
+			log.Println("error scoring exam", ses, task.TID(), err)
+			continue
+		}
+		ses.MistakesMade(task, res.Mistakes)
+		ses.TaskScore(task, res.Score)
+		if len(res.Messages) == 0 {
+			ses.TaskCompleted(task)
+		}
+	}
+	log.Println("exam submitted", ses, ch.Lecture().Id, ch.StateId())
+}
+
+// examData is used to show the state of an exam on the chapter page
+type examData struct {
+	Minutes   int
+	Start     string
+	Open      bool
+	Running   bool
+	Finished  bool
+	Released  bool
+	Remaining int
+}
+
+// createExamData handles the exam related requests of the chapter page
+func createExamData(r *http.Request, ch *data.Chapter, state *data.LectureState, ses *session.Session) *examData {
+	if !ch.IsExam() || ses == nil {
+		return nil
+	}
+
+	es := state.Exam(ch)
+	now := time.Now()
+
+	if r.Method == http.MethodPost {
+		err := r.ParseForm()
+		if err != nil {
+			panic(err)
+		}
+		attempt := ses.Exam(ch)
+		if r.Form.Get("startExam") == "true" && es.IsOpen(now) && attempt.Started == 0 {
+			ses.StartExam(ch)
+			log.Println("exam started", ses, ch.Lecture().Id, ch.StateId())
+		}
+		if r.Form.Get("submitExam") == "true" && attempt.Started > 0 {
+			finishExam(ch, state, ses)
+		}
+	}
+
+	attempt := ses.Exam(ch)
+	if attempt.IsFinished(ch, now) {
+		finishExam(ch, state, ses)
+	}
+
+	ed := examData{
+		Minutes:  ch.ExamMinutes,
+		Open:     es.IsOpen(now),
+		Running:  attempt.IsRunning(ch, now),
+		Finished: attempt.IsFinished(ch, now),
+		Released: es.Released,
+	}
+	if es.Start > 0 {
+		ed.Start = time.Unix(es.Start, 0).Format(examTimeFormat)
+	}
+	if ed.Running {
+		ed.Remaining = int(attempt.Deadline(ch).Sub(now).Seconds())
+	}
+	return &ed
+}
+
+// examTask handles a task which is part of an exam.
+// While the exam is running, the answers are only stored. There is no
+// feedback given to the user. After the exam is finished, the answers
+// are shown read only, and if released, together with the results.
+func examTask(r *http.Request, td *taskData, params data.Params, state *data.LectureState, ses *session.Session) {
+	task := td.Task
+	ch := task.Chapter()
+	now := time.Now()
+
+	td.Exam = true
+	td.ShowSolutionsButton = false
+
+	attempt := ses.Exam(ch)
+	if attempt.IsRunning(ch, now) {
+		if r.Method == http.MethodPost {
+			err := r.ParseForm()
+			if err != nil {
+				panic(err)
+			}
+			answers := map[data.InputId]string{}
+			for _, i := range task.Input {
+				answers[i.Id] = formValue(r.Form, i)
+			}
+			ses.ExamAnswers(task, answers)
+			td.ExamSaved = true
+			attempt = ses.Exam(ch)
+		}
+		td.ExamRemaining = int(attempt.Deadline(ch).Sub(now).Seconds())
+	} else {
+		finishExam(ch, state, ses)
+		attempt = ses.Exam(ch)
+		td.Locked = true
+		if state.Exam(ch).Released {
+			res := task.Score(examAnswers(task, attempt), params, false)
+			td.Result = res.Messages
+			td.validation = res
+			td.HasResult = true
+			td.Ok = len(res.Messages) == 0
+			td.ExamReleased = true
+		}
+	}
+	td.Answers = examAnswers(task, attempt)
+
+	if nTask := taskAfter(ch.SelectedTasks(selection(state, ses)), task); nTask != nil {
+		td.Next = fmt.Sprintf("/task/%s/%v/%d/", ch.Lecture().Id, ch.Num(), nTask.Num())
+	}
+}
+
+var examTemp = Templates.Lookup("exam.html")
+
+// ExamViewData is used to render the exam overview of the admin
+type ExamViewData struct {
+	Title     string
+	Chapter   string
+	Minutes   int
+	HasPoints bool
+	Users     []ExamUser
+}
+
+// ExamUser contains the exam state of a single user
+type ExamUser struct {
+	User      string
+	Started   string
+	Submitted string
+	Status    string
+	Points    float64
+	MaxPoints float64
+}
+
+// CreateExam creates the handler showing the users who have started
+// and submitted an exam.
+func CreateExam(lectures *data.Lectures, sessions *session.Sessions, states *data.LectureStates) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		lecture, err := lectures.GetLecture(data.LectureId(query.Get("id")))
+		if err != nil {
+			panic(err)
+		}
+		cn, err := data.NewChapterNum(query.Get("c"))
+		if err != nil {
+			panic(err)
+		}
+		ch, err := lecture.GetChapter(cn)
+		if err != nil {
+			panic(err)
+		}
+		if !ch.IsExam() {
+			panic("chapter is not an exam")
+		}
+
+		stats, err := sessions.ExamStats(ch)
+		if err != nil {
+			panic(err)
+		}
+
+		state := states.Get(lecture.Id)
+		now := time.Now()
+		ev := ExamViewData{
+			Title:     lecture.Title,
+			Chapter:   ch.FullTitle(),
+			Minutes:   ch.ExamMinutes,
+			HasPoints: lecture.HasPoints(),
+		}
+		for _, s := range stats {
+			u := ExamUser{User: s.User}
+			if s.Started > 0 {
+				u.Started = time.Unix(s.Started, 0).Format(examTimeFormat)
+			}
+			switch {
+			case s.Submitted > 0:
+				u.Status = "abgegeben"
+				u.Submitted = time.Unix(s.Submitted, 0).Format(examTimeFormat)
+			case s.IsRunning(ch, now):
+				u.Status = "läuft"
+			case s.Started > 0:
+				u.Status = "Zeit abgelaufen"
+			}
+			for _, task := range ch.SelectedTasks(poolSelection(&state, s.User)) {
+				u.MaxPoints += task.MaxPoints()
+				res, err := examScore(task, s.ExamAttempt, s.User)
+				if err == nil {
+					u.Points += res.Score * task.MaxPoints()
+				}
+			}
+			ev.Users = append(ev.Users, u)
+		}
+		sort.Slice(ev.Users, func(i, j int) bool {
+			return ev.Users[i].User < ev.Users[j].User
+		})
+
+		err = examTemp.Execute(w, ev)
+		if err != nil {
+			log.Println(err)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"html/template"
 	"io"
 	"log"
+	"math"
 	"mime"
 	"net/http"
 	"net/url"
@@ -54,16 +55,26 @@ var funcMap = template.FuncMap{
 		return i - 1
 	},
 	"markdown": func(raw string, LId data.LectureId) template.HTML { return fromMarkdown(raw, LId) },
+	"points":   formatPoints,
 }
 
-func fromMarkdown(raw string, LId data.LectureId) template.HTML {
+// formatPoints formats the points rounded to one decimal place
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(math.Round(p*10)/10, 'f', -1, 64)
+}
+
+func parseMarkdown(raw string) ast.Node {
 	// create Markdown parser with extensions
 	extensions := parser.CommonExtensions |
 		parser.AutoHeadingIDs |
 		parser.NoEmptyLineBeforeBlock |
 		parser.SuperSubscript
 	p := parser.NewWithExtensions(extensions)
-	doc := p.Parse([]byte(raw))
+	return p.Parse([]byte(raw))
+}
+
+func fromMarkdown(raw string, LId data.LectureId) template.HTML {
+	doc := parseMarkdown(raw)
 
 	if d, ok := doc.(*ast.Document); ok {
 		if len(d.Children) == 1 {
@@ -177,31 +188,173 @@ func CreateMain(lectures *data.Lectures, logout bool, states *data.LectureStates
 var lectureTemp = Templates.Lookup("lecture.html")
 
 type lectureData struct {
-	Lecture *data.Lecture
-	session *session.Session
+	Lecture   *data.Lecture
+	session   *session.Session
+	state     data.LectureState
+	selection data.Selection
 }
 
 // Completed returns the number of completed tasks in the given chapter
 func (cd lectureData) Completed(cnum data.ChapterNum) int {
-	if cd.session == nil {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
 		return 0
 	}
+	return completedTasks(ch, cd.session, &cd.state)
+}
 
+// Tasks returns the number of tasks in the given chapter
+func (cd lectureData) Tasks(cnum data.ChapterNum) int {
 	ch, err := cd.Lecture.GetChapter(cnum)
 	if err != nil {
 		return 0
 	}
+	return ch.Tasks(cd.selection)
+}
+
+// Points returns the points reached in the given chapter
+func (cd lectureData) Points(cnum data.ChapterNum) float64 {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
+		return 0
+	}
+	return chapterPoints(ch, cd.session, &cd.state)
+}
+
+// MaxPoints returns the points which can be reached in the given chapter
+func (cd lectureData) MaxPoints(cnum data.ChapterNum) float64 {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
+		return 0
+	}
+	return ch.MaxPoints(cd.selection)
+}
+
+// Visible returns true if the given chapter is visible to the user
+func (cd lectureData) Visible(cnum data.ChapterNum) bool {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
+		return false
+	}
+	return isChapterVisible(ch, &cd.state, cd.session)
+}
+
+// Hidden returns true if the given chapter is hidden for the students
+func (cd lectureData) Hidden(cnum data.ChapterNum) bool {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
+		return false
+	}
+	return !cd.state.IsVisible(ch, time.Now())
+}
 
+// TotalPoints returns the points reached in the visible chapters of the lecture
+func (cd lectureData) TotalPoints() float64 {
+	p := 0.0
+	for _, ch := range cd.Lecture.Chapter {
+		if isChapterVisible(ch, &cd.state, cd.session) {
+			p += chapterPoints(ch, cd.session, &cd.state)
+		}
+	}
+	return p
+}
+
+// TotalMaxPoints returns the points which can be reached in the visible chapters of the lecture
+func (cd lectureData) TotalMaxPoints() float64 {
+	p := 0.0
+	for _, ch := range cd.Lecture.Chapter {
+		if isChapterVisible(ch, &cd.state, cd.session) {
+			p += ch.MaxPoints(cd.selection)
+		}
+	}
+	return p
+}
+
+// Locked returns the reason why the given chapter is locked.
+// An empty string is returned if the chapter is available.
+func (cd lectureData) Locked(cnum data.ChapterNum) string {
+	ch, err := cd.Lecture.GetChapter(cnum)
+	if err != nil {
+		return ""
+	}
+	return lockReason(ch, &cd.state, cd.session)
+}
+
+// lockReason returns the reason why the chapter is locked for the user.
+// A chapter is locked, if a required chapter or a required chapter of one
+// of its parents is not completed up to the required threshold.
+// An empty string is returned if the chapter is available.
+func lockReason(ch *data.Chapter, state *data.LectureState, ses *session.Session) string {
+	if state.ShowAllTasks || (ses != nil && ses.IsAdmin()) {
+		return ""
+	}
+	for c := ch; c != nil; c = c.ParentChapter {
+		for _, r := range c.Requirements() {
+			tasks := r.Chapter.Tasks(selection(state, ses))
+			if tasks == 0 {
+				continue
+			}
+			completed := float64(completedTasks(r.Chapter, ses, state)) / float64(tasks)
+			if completed < r.Threshold {
+				return fmt.Sprintf("Zuerst müssen %d%% der Fragen in '%s' gelöst werden.", r.Percent(), r.Chapter.FullTitle())
+			}
+		}
+	}
+	return ""
+}
+
+// isChapterVisible returns true if the chapter is visible to the user.
+// Admins can see all chapters.
+func isChapterVisible(ch *data.Chapter, state *data.LectureState, ses *session.Session) bool {
+	return (ses != nil && ses.IsAdmin()) || state.IsVisible(ch, time.Now())
+}
+
+func completedTasks(ch *data.Chapter, ses *session.Session, state *data.LectureState) int {
+	if ses == nil {
+		return 0
+	}
 	c := 0
-	for task := range ch.Iter {
-		if cd.session.IsTaskCompleted(task) {
+	for task := range ch.Selected(selection(state, ses)) {
+		if ses.IsTaskCompleted(task) && isResultVisible(task, state, ses) {
 			c++
 		}
 	}
 	return c
 }
 
-func CreateLecture(lectures *data.Lectures) http.Handler {
+func chapterPoints(ch *data.Chapter, ses *session.Session, state *data.LectureState) float64 {
+	if ses == nil {
+		return 0
+	}
+	p := 0.0
+	for task := range ch.Selected(selection(state, ses)) {
+		if isResultVisible(task, state, ses) {
+			p += ses.Score(task) * task.MaxPoints()
+		}
+	}
+	return p
+}
+
+// selection returns the tasks of the pools visible to the user.
+// The pool tasks are drawn for each user. Admins can see all tasks.
+func selection(state *data.LectureState, ses *session.Session) data.Selection {
+	if ses == nil {
+		return data.Selection{All: state.ShowWholePool}
+	}
+	sel := poolSelection(state, ses.PersistToken())
+	sel.All = sel.All || ses.IsAdmin()
+	return sel
+}
+
+// poolSelection returns the tasks of the pools drawn for the given user
+func poolSelection(state *data.LectureState, persistToken string) data.Selection {
+	return data.Selection{
+		Seed: persistToken + strconv.Itoa(state.PoolRound),
+		All:  state.ShowWholePool,
+	}
+}
+
+func CreateLecture(lectures *data.Lectures, states *data.LectureStates) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		lectureId, _ := getLectureFromPath(r.URL.Path)
 		lecture, err := lectures.GetLecture(lectureId)
@@ -210,8 +363,9 @@ func CreateLecture(lectures *data.Lectures) http.Handler {
 		}
 
 		ses, _ := r.Context().Value(session.Key).(*session.Session)
+		state := states.Get(lecture.Id)
 
-		err = lectureTemp.Execute(w, lectureData{Lecture: lecture, session: ses})
+		err = lectureTemp.Execute(w, lectureData{Lecture: lecture, session: ses, state: state, selection: selection(&state, ses)})
 		if err != nil {
 			log.Println(err)
 		}
@@ -259,9 +413,16 @@ var (
 )
 
 type chapterData struct {
-	Chapter *data.Chapter
-	session *session.Session
-	state   data.LectureState
+	Chapter   *data.Chapter
+	Exam      *examData
+	session   *session.Session
+	state     data.LectureState
+	selection data.Selection
+}
+
+// Tasks returns the tasks of the chapter visible to the user
+func (cd chapterData) Tasks() []*data.Task {
+	return cd.Chapter.SelectedTasks(cd.selection)
 }
 
 func (cd chapterData) Completed(num data.TaskNum) bool {
@@ -272,26 +433,88 @@ func (cd chapterData) Completed(num data.TaskNum) bool {
 	if err != nil {
 		return false
 	}
-	return cd.session.IsTaskCompleted(task)
+	return cd.session.IsTaskCompleted(task) && isResultVisible(task, &cd.state, cd.session)
 }
 
 func (cd chapterData) CompletedTasks(num int) int {
-	if cd.session == nil {
+	if num < 0 || num >= len(cd.Chapter.Chapter) {
 		return 0
 	}
+	return completedTasks(cd.Chapter.Chapter[num], cd.session, &cd.state)
+}
+
+// Hidden returns true if the chapter is hidden for the students
+func (cd chapterData) Hidden() bool {
+	return !cd.state.IsVisible(cd.Chapter, time.Now())
+}
+
+// ChapterVisible returns true if the given sub chapter is visible to the user
+func (cd chapterData) ChapterVisible(num int) bool {
+	if num < 0 || num >= len(cd.Chapter.Chapter) {
+		return false
+	}
+	return isChapterVisible(cd.Chapter.Chapter[num], &cd.state, cd.session)
+}
+
+// ChapterHidden returns true if the given sub chapter is hidden for the students
+func (cd chapterData) ChapterHidden(num int) bool {
+	if num < 0 || num >= len(cd.Chapter.Chapter) {
+		return false
+	}
+	return !cd.state.IsVisible(cd.Chapter.Chapter[num], time.Now())
+}
+
+// ChapterLocked returns the reason why the given sub chapter is locked.
+// An empty string is returned if the sub chapter is available.
+func (cd chapterData) ChapterLocked(num int) string {
+	if num < 0 || num >= len(cd.Chapter.Chapter) {
+		return ""
+	}
+	return lockReason(cd.Chapter.Chapter[num], &cd.state, cd.session)
+}
+
+// ChapterTasks returns the number of tasks in the given sub chapter
+func (cd chapterData) ChapterTasks(num int) int {
 	if num < 0 || num >= len(cd.Chapter.Chapter) {
 		return 0
 	}
+	return cd.Chapter.Chapter[num].Tasks(cd.selection)
+}
 
-	c := 0
-	chapter := cd.Chapter.Chapter[num]
-	for task := range chapter.Iter {
-		if cd.session.IsTaskCompleted(task) {
-			c++
-		}
+// Points returns the points reached in the given task
+func (cd chapterData) Points(num data.TaskNum) float64 {
+	if cd.session == nil {
+		return 0
 	}
+	task, err := cd.Chapter.GetTask(num)
+	if err != nil || !isResultVisible(task, &cd.state, cd.session) {
+		return 0
+	}
+	return cd.session.Score(task) * task.MaxPoints()
+}
 
-	return c
+// ChapterPoints returns the points reached in the given sub chapter.
+// If num is negative, the points of the chapter itself are returned.
+func (cd chapterData) ChapterPoints(num int) float64 {
+	if num < 0 {
+		return chapterPoints(cd.Chapter, cd.session, &cd.state)
+	}
+	if num >= len(cd.Chapter.Chapter) {
+		return 0
+	}
+	return chapterPoints(cd.Chapter.Chapter[num], cd.session, &cd.state)
+}
+
+// ChapterMaxPoints returns the points which can be reached in the given sub chapter.
+// If num is negative, the points of the chapter itself are returned.
+func (cd chapterData) ChapterMaxPoints(num int) float64 {
+	if num < 0 {
+		return cd.Chapter.MaxPoints(cd.selection)
+	}
+	if num >= len(cd.Chapter.Chapter) {
+		return 0
+	}
+	return cd.Chapter.Chapter[num].MaxPoints(cd.selection)
 }
 
 func (cd chapterData) IsAvail(num data.TaskNum) bool {
@@ -306,7 +529,25 @@ func (cd chapterData) IsAvail(num data.TaskNum) bool {
 }
 
 func IsTaskAvail(task *data.Task, state *data.LectureState, session *session.Session) bool {
-	if task.Num() == 0 {
+	sel := selection(state, session)
+	if !task.IsSelected(sel) {
+		return false
+	}
+
+	if !isChapterVisible(task.Chapter(), state, session) || lockReason(task.Chapter(), state, session) != "" {
+		return false
+	}
+
+	if task.Chapter().IsExam() {
+		if session == nil {
+			return false
+		}
+		// the tasks of an exam are available after the exam is started
+		return session.IsAdmin() || session.Exam(task.Chapter()).Started > 0
+	}
+
+	tasks := task.Chapter().SelectedTasks(sel)
+	if tasks[0] == task {
 		return true
 	}
 
@@ -322,8 +563,27 @@ func IsTaskAvail(task *data.Task, state *data.LectureState, session *session.Ses
 		return true
 	}
 
-	beforeTask, _ := task.Chapter().GetTask(task.Num() - 1)
-	return session.IsTaskCompleted(beforeTask)
+	return session.IsTaskCompleted(taskBefore(tasks, task))
+}
+
+// taskBefore returns the task in front of the given task
+func taskBefore(tasks []*data.Task, task *data.Task) *data.Task {
+	for i := 1; i < len(tasks); i++ {
+		if tasks[i] == task {
+			return tasks[i-1]
+		}
+	}
+	return nil
+}
+
+// taskAfter returns the task following the given task
+func taskAfter(tasks []*data.Task, task *data.Task) *data.Task {
+	for i := 0; i < len(tasks)-1; i++ {
+		if tasks[i] == task {
+			return tasks[i+1]
+		}
+	}
+	return nil
 }
 
 func CreateChapter(lectures *data.Lectures, states *data.LectureStates) http.Handler {
@@ -342,10 +602,22 @@ func CreateChapter(lectures *data.Lectures, states *data.LectureStates) http.Han
 
 		ses, _ := r.Context().Value(session.Key).(*session.Session)
 
+		state := states.Get(lecture.Id)
+		if !isChapterVisible(chapter, &state, ses) {
+			panic("chapter not available")
+		}
+		if reason := lockReason(chapter, &state, ses); reason != "" {
+			panic(reason)
+		}
+
+		cd := chapterData{Chapter: chapter, session: ses, state: state, selection: selection(&state, ses)}
+		if ses == nil || !ses.IsAdmin() {
+			cd.Exam = createExamData(r, chapter, &state, ses)
+		}
 		if chapter.HasSubChapter() {
-			err = mChapterTemp.Execute(w, chapterData{Chapter: chapter, session: ses, state: states.Get(lecture.Id)})
+			err = mChapterTemp.Execute(w, cd)
 		} else {
-			err = chapterTemp.Execute(w, chapterData{Chapter: chapter, session: ses, state: states.Get(lecture.Id)})
+			err = chapterTemp.Execute(w, cd)
 		}
 		if err != nil {
 			log.Println(err)
@@ -357,6 +629,8 @@ var taskTemp = Templates.Lookup("task.html")
 
 type taskData struct {
 	Task                *data.Task
+	Params              data.Params
+	seed                string
 	HasResult           bool
 	ShowSolutionsButton bool
 	Answers             data.DataMap
@@ -365,6 +639,42 @@ type taskData struct {
 	Ok                  bool
 	ShowReload          bool
 	ReloadError         error
+	// Locked is true if the maximum number of attempts is reached
+	Locked bool
+	// AttemptsLeft is the number of attempts left, zero if there is no limit
+	AttemptsLeft   int
+	CanRequestHint bool
+	// Exam is true if the task is part of an exam
+	Exam bool
+	// ExamSaved is true if the answers are stored in the exam
+	ExamSaved bool
+	// ExamReleased is true if the results of the exam are visible
+	ExamReleased bool
+	// ExamRemaining is the remaining time of the exam in seconds
+	ExamRemaining int
+	// Tag is set if the task was opened from the practice view of this tag
+	Tag string
+	// Review is true if the task is answered in the review mode
+	Review bool
+	// NextReview is the date of the next review
+	NextReview string
+	// Recommended is the task recommended after the task is completed
+	Recommended *data.Task
+	// RecommendedPath is the path of the recommended task
+	RecommendedPath string
+	failed          int
+	hintsRequested  int
+	validation      *data.Result
+}
+
+// Hints returns the hints visible at the given input
+func (td *taskData) Hints(id data.InputId) []*data.Hint {
+	return td.Task.Hints(id, td.failed, td.hintsRequested)
+}
+
+// HasMoreHints returns true if the user can request a further hint
+func (td *taskData) HasMoreHints() bool {
+	return td.CanRequestHint && !td.Locked && td.Task.HasMoreHints(td.failed, td.hintsRequested)
 }
 
 func (td *taskData) GetAnswer(id data.InputId) string {
@@ -381,6 +691,27 @@ func (td *taskData) GetAnswer(id data.InputId) string {
 	return ""
 }
 
+// TableCell returns the value of a cell of a truth table input
+func (td *taskData) TableCell(id data.InputId, row int) string {
+	a, _ := td.Answers[id].(string)
+	return data.TableCell(a, row)
+}
+
+// MatrixCell returns the value of a cell of a matrix input
+func (td *taskData) MatrixCell(id data.InputId, row, col int) string {
+	return data.MatrixCell(td.Answers[id], row, col)
+}
+
+// Subst substitutes the task parameters in the given markdown
+func (td *taskData) Subst(md string) string {
+	return td.Params.Substitute(md)
+}
+
+// Options returns the options of the given input in the order shown to the user
+func (td *taskData) Options(i *data.Input) []*data.Option {
+	return i.Options(td.seed + string(td.Task.TID()))
+}
+
 func (td *taskData) GetResult(id data.InputId) string {
 	return td.Result[id]
 }
@@ -390,12 +721,40 @@ func (td *taskData) HasHook(id data.InputId) bool {
 		return false
 	}
 
-	if !td.Task.InputHasValidator(id) {
-		return false
+	return td.validation.Accepted(id)
+}
+
+// readAnswers creates the answers of the task from the given raw values
+func readAnswers(task *data.Task, get func(i *data.Input) string) data.DataMap {
+	answers := data.DataMap{}
+	for _, i := range task.Input {
+		a := get(i)
+		switch i.Type {
+		case data.Checkbox:
+			answers[i.Id] = strings.ToLower(a) == "on"
+		case data.Matrix:
+			answers[i.Id] = i.SplitMatrix(a)
+		default:
+			answers[i.Id] = a
+		}
 	}
+	return answers
+}
 
-	_, isMessage := td.Result[id]
-	return !isMessage
+// formValue returns the raw answer of the input sent by the browser.
+// The cells of a truth table or a matrix are sent as separate fields.
+func formValue(form url.Values, i *data.Input) string {
+	if i.IsTruthTable() {
+		return i.JoinTable(func(row int) string {
+			return form.Get(fmt.Sprintf("input_%s_%d", i.Id, row))
+		})
+	}
+	if i.IsMatrix() {
+		return i.JoinMatrix(func(row, col int) string {
+			return form.Get(fmt.Sprintf("input_%s_%d_%d", i.Id, row, col))
+		})
+	}
+	return form.Get("input_" + string(i.Id))
 }
 
 func CreateTask(lectures *data.Lectures, states *data.LectureStates) http.Handler {
@@ -416,6 +775,7 @@ func CreateTask(lectures *data.Lectures, states *data.LectureStates) http.Handle
 			nl, reloadError = lectures.Reload(lecture.Id)
 			if nl != nil {
 				lecture = nl
+				states.Migrate(lectures)
 			}
 		}
 
@@ -425,57 +785,135 @@ func CreateTask(lectures *data.Lectures, states *data.LectureStates) http.Handle
 		}
 
 		state := states.Get(lecture.Id)
-		showSolutions := state.ShowSolutions
+		showSolutions := state.SolutionsReleased(task.Chapter(), time.Now())
 		showReload := false
+		seed := ""
 		ses, _ := r.Context().Value(session.Key).(*session.Session)
 		if ses != nil {
 			showSolutions = showSolutions || ses.IsAdmin()
 			showReload = ses.IsAdmin() && lecture.CanReload()
+			seed = ses.PersistToken()
 
 			if !IsTaskAvail(task, &state, ses) {
 				panic("task not available")
 			}
 		}
 
+		params, err := task.CreateParams(seed)
+		if err != nil {
+			panic(err)
+		}
+
 		td := taskData{
 			Task:                task,
+			Params:              params,
+			seed:                seed,
 			Answers:             data.DataMap{},
 			ShowSolutionsButton: showSolutions,
 			ShowReload:          showReload,
 			ReloadError:         reloadError,
 		}
+		if tag := r.FormValue("tag"); task.HasTag(tag) {
+			td.Tag = tag
+		}
+		td.Review = r.FormValue("review") == "true" && ses != nil && ses.IsTaskCompleted(task) && !task.Chapter().IsExam()
 
-		if r.Method == http.MethodPost {
+		if ses != nil && !ses.IsAdmin() && task.Chapter().IsExam() {
+			examTask(r, &td, params, &state, ses)
+			err = taskTemp.Execute(w, &td)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+
+		limit := task.AttemptLimit()
+		isLocked := func() bool {
+			return limit > 0 && ses != nil && !ses.IsAdmin() && !ses.IsTaskCompleted(task) && ses.FailedAttempts(task) >= limit
+		}
+
+		if r.Method == http.MethodPost && !isLocked() {
 			err = r.ParseForm()
 			if err != nil {
 				panic(err)
 			}
-			for _, i := range task.Input {
-				a := r.Form.Get("input_" + string(i.Id))
-				switch i.Type {
-				case data.Checkbox:
-					td.Answers[i.Id] = strings.ToLower(a) == "on"
-				default:
-					td.Answers[i.Id] = a
+			td.Answers = readAnswers(task, func(i *data.Input) string {
+				return formValue(r.Form, i)
+			})
+			if r.Form.Get("nextHint") != "" {
+				// only a request which reveals a further hint is counted
+				if ses != nil && task.HasMoreHints(ses.FailedAttempts(task), ses.HintsRequested(task)) {
+					ses.HintRequested(task)
+					log.Println("hint requested", ses, task.TID())
 				}
-			}
-			showResult := showSolutions && r.Form.Get("showResult") != ""
-			td.Result = task.Validate(td.Answers, showResult)
-			if len(td.Result) == 0 {
+			} else {
+				showResult := showSolutions && r.Form.Get("showResult") != ""
+				res := task.Score(td.Answers, params, showResult)
+				td.Result = res.Messages
+				td.validation = res
+				if td.Review {
+					// a review does not modify the data used by the statistics
+					if due, ok := ses.ReviewResult(task, len(td.Result) == 0); ok {
+						td.NextReview = due.Format("02.01.2006")
+					}
+					td.Ok = len(td.Result) == 0
+				} else {
+					if ses != nil {
+						ses.MistakesMade(task, res.Mistakes)
+						ses.TaskScore(task, task.ReduceScore(res.Score, ses.HintsRequested(task)))
+					}
+					if len(td.Result) == 0 {
+
+						if ses != nil {
+							ses.TaskCompleted(task)
+						}
 
-				if ses != nil {
-					ses.TaskCompleted(task)
+						td.Ok = true
+					} else if ses != nil {
+						ses.TaskFailed(task)
+					}
 				}
+				td.HasResult = true
+			}
+		}
 
-				td.Ok = true
+		if ses != nil {
+			td.failed = ses.FailedAttempts(task)
+			td.hintsRequested = ses.HintsRequested(task)
+			td.CanRequestHint = !ses.IsTaskCompleted(task)
+		}
+
+		if isLocked() {
+			td.Locked = true
+			if showSolutions {
+				if td.HasResult {
+					td.Result = task.Validate(td.Answers, params, true)
+				} else {
+					td.Result = task.Explanations(params)
+				}
 			}
-			td.HasResult = true
+		} else if limit > 0 && ses != nil && !ses.IsTaskCompleted(task) {
+			td.AttemptsLeft = limit - ses.FailedAttempts(task)
 		}
 
 		if ses != nil && ses.IsTaskCompleted(task) {
-			if nTask, err := task.Chapter().GetTask(tn + 1); err == nil {
+			if td.Review {
+				if nTask := nextReviewTask(lecture, task, &state, ses); nTask != nil {
+					td.Next = reviewPath(nTask)
+				}
+			} else if td.Tag != "" {
+				if nTask := nextPracticeTask(practiceTasks(lecture, td.Tag, &state, ses), task, &state, ses); nTask != nil {
+					td.Next = practicePath(nTask, td.Tag)
+				}
+			} else if nTask := taskAfter(task.Chapter().SelectedTasks(selection(&state, ses)), task); nTask != nil {
 				td.Next = fmt.Sprintf("/task/%s/%v/%d/", lecture.Id, cn, nTask.Num())
 			}
+			if !td.Review {
+				td.Recommended = recommend(lecture, task, &state, ses)
+				if td.Recommended != nil {
+					td.RecommendedPath = recommendedPath(td.Recommended, ses)
+				}
+			}
 		}
 
 		err = taskTemp.Execute(w, &td)
@@ -509,7 +947,7 @@ func CreateImages(lectures *data.Lectures) http.Handler {
 
 var adminTemp = Templates.Lookup("admin.html")
 
-func CreateAdmin(lectures *data.Lectures) http.Handler {
+func CreateAdmin(lectures *data.Lectures, states *data.LectureStates) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			err := r.ParseMultipartForm(32 << 20)
@@ -531,6 +969,7 @@ func CreateAdmin(lectures *data.Lectures) http.Handler {
 			if err != nil {
 				panic(err)
 			}
+			states.Migrate(lectures)
 		}
 		err := adminTemp.Execute(w, lectures)
 		if err != nil {
@@ -542,8 +981,9 @@ func CreateAdmin(lectures *data.Lectures) http.Handler {
 var statsViewTemp = Templates.Lookup("statistics.html")
 
 type StatsData struct {
-	Title   string
-	Chapter []StatsChapter
+	Title     string
+	HasPoints bool
+	Chapter   []StatsChapter
 }
 type StatsChapter struct {
 	Title string
@@ -552,6 +992,19 @@ type StatsChapter struct {
 type StatsTask struct {
 	Task  string
 	Count int
+	// Attempts is the number of failed attempts of all users
+	Attempts int
+	// Hints is the number of hints requested by all users
+	Hints     int
+	MaxPoints float64
+	// Points is the mean of the points reached by the users who tried the task
+	Points float64
+	// Mistakes contains the number of times each defined mistake was detected
+	Mistakes []StatsMistake
+}
+type StatsMistake struct {
+	Mistake string
+	Count   int
 }
 
 func CreateStatistics(lectures *data.Lectures, sessions *session.Sessions) http.Handler {
@@ -567,7 +1020,7 @@ func CreateStatistics(lectures *data.Lectures, sessions *session.Sessions) http.
 			panic(err)
 		}
 
-		stats := StatsData{Title: lecture.Title}
+		stats := StatsData{Title: lecture.Title, HasPoints: lecture.HasPoints()}
 		collectChapter(lecture.Chapter, statsMap, &stats, time.Now().AddDate(0, -6, 0).Unix())
 
 		err = statsViewTemp.Execute(w, stats)
@@ -577,7 +1030,7 @@ func CreateStatistics(lectures *data.Lectures, sessions *session.Sessions) http.
 	})
 }
 
-func collectChapter(chap data.ChapterList, statsMap []map[data.TaskId]int64, stats *StatsData, oldest int64) {
+func collectChapter(chap data.ChapterList, statsMap []session.LectureStats, stats *StatsData, oldest int64) {
 	for _, c := range chap {
 		if c.HasSubChapter() {
 			collectChapter(c.Chapter, statsMap, stats, oldest)
@@ -585,14 +1038,35 @@ func collectChapter(chap data.ChapterList, statsMap []map[data.TaskId]int64, sta
 			chapter := StatsChapter{Title: c.Title}
 			for _, t := range c.Task {
 				counter := 0
+				tried := 0
+				attempts := 0
+				hints := 0
+				scoreSum := 0.0
 				for _, s := range statsMap {
-					if date, ok := s[t.TID()]; ok {
+					if date, ok := s.Completed[t.TID()]; ok {
 						if date > oldest {
 							counter++
 						}
 					}
+					if score := s.Score(t.TID()); score > 0 {
+						tried++
+						scoreSum += score
+					}
+					attempts += s.Attempts[t.TID()]
+					hints += s.Hints[t.TID()]
 				}
-				chapter.Task = append(chapter.Task, StatsTask{Task: t.Name, Count: counter})
+				st := StatsTask{Task: t.Name, Count: counter, Attempts: attempts, Hints: hints, MaxPoints: t.MaxPoints()}
+				if tried > 0 {
+					st.Points = scoreSum / float64(tried) * t.MaxPoints()
+				}
+				for _, key := range t.MistakeKeys() {
+					count := 0
+					for _, s := range statsMap {
+						count += s.Mistakes[t.TID()][key]
+					}
+					st.Mistakes = append(st.Mistakes, StatsMistake{Mistake: key, Count: count})
+				}
+				chapter.Task = append(chapter.Task, st)
 			}
 			stats.Chapter = append(stats.Chapter, chapter)
 		}
@@ -603,7 +1077,53 @@ var settingsTemp = Templates.Lookup("settings.html")
 
 type settingsData struct {
 	Title    string
+	Id       data.LectureId
 	Settings data.LectureState
+	Chapters []settingsChapter
+	Exams    []settingsExam
+}
+
+// settingsChapter contains the schedule of a chapter
+type settingsChapter struct {
+	Id            string
+	Title         string
+	VisibleFrom   string
+	VisibleUntil  string
+	SolutionsFrom string
+}
+
+// settingsExam contains the settings of an exam chapter
+type settingsExam struct {
+	Id       string
+	Num      data.ChapterNum
+	Title    string
+	Start    string
+	Released bool
+}
+
+// settingsTimeFormat is the format used by the datetime-local input
+const settingsTimeFormat = "2006-01-02T15:04"
+
+// parseSettingsTime parses the value of a datetime-local input.
+// An empty value results in zero.
+func parseSettingsTime(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	t, err := time.ParseInLocation(settingsTimeFormat, value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t.Unix()
+}
+
+// formatSettingsTime formats a time for a datetime-local input.
+// Zero results in an empty value.
+func formatSettingsTime(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).Format(settingsTimeFormat)
 }
 
 func CreateSettings(lectures *data.Lectures, states *data.LectureStates) http.Handler {
@@ -625,6 +1145,23 @@ func CreateSettings(lectures *data.Lectures, states *data.LectureStates) http.Ha
 			settings.ShowSolutions = r.Form.Get("showSolutions") == "true"
 			settings.ShowAllTasks = r.Form.Get("showAllTasks") == "true"
 			settings.Disabled = r.Form.Get("disabled") == "true"
+			settings.ShowWholePool = r.Form.Get("showWholePool") == "true"
+			if r.Form.Get("redraw") == "true" {
+				settings.PoolRound++
+			}
+			for _, ch := range lecture.AllChapters() {
+				settings.SetSchedule(ch, data.ChapterSchedule{
+					VisibleFrom:   parseSettingsTime(r.Form.Get("visibleFrom_" + ch.StateId())),
+					VisibleUntil:  parseSettingsTime(r.Form.Get("visibleUntil_" + ch.StateId())),
+					SolutionsFrom: parseSettingsTime(r.Form.Get("solutionsFrom_" + ch.StateId())),
+				})
+			}
+			for _, ch := range lecture.Exams() {
+				settings.SetExam(ch, data.ExamState{
+					Start:    parseSettingsTime(r.Form.Get("examStart_" + ch.StateId())),
+					Released: r.Form.Get("examReleased_"+ch.StateId()) == "true",
+				})
+			}
 
 			err = states.SetState(id, settings)
 			if err != nil {
@@ -632,7 +1169,29 @@ func CreateSettings(lectures *data.Lectures, states *data.LectureStates) http.Ha
 			}
 		}
 
-		err = settingsTemp.Execute(w, settingsData{Title: lecture.Title, Settings: settings})
+		sd := settingsData{Title: lecture.Title, Id: lecture.Id, Settings: settings}
+		for _, ch := range lecture.AllChapters() {
+			cs := settings.Schedule(ch)
+			sd.Chapters = append(sd.Chapters, settingsChapter{
+				Id:            ch.StateId(),
+				Title:         ch.FullTitle(),
+				VisibleFrom:   formatSettingsTime(cs.VisibleFrom),
+				VisibleUntil:  formatSettingsTime(cs.VisibleUntil),
+				SolutionsFrom: formatSettingsTime(cs.SolutionsFrom),
+			})
+		}
+		for _, ch := range lecture.Exams() {
+			es := settings.Exam(ch)
+			sd.Exams = append(sd.Exams, settingsExam{
+				Id:       ch.StateId(),
+				Num:      ch.Num(),
+				Title:    ch.FullTitle(),
+				Start:    formatSettingsTime(es.Start),
+				Released: es.Released,
+			})
+		}
+
+		err = settingsTemp.Execute(w, sd)
 		if err != nil {
 			log.Println(err)
 		}
@@ -1,12 +1,21 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/xml"
+	"fmt"
+	"github.com/hneemann/objectDB/serialize"
 	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server/session"
 	"github.com/stretchr/testify/assert"
+	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_getStrFromPath(t *testing.T) {
@@ -80,3 +89,732 @@ func Test_MathMLInResult(t *testing.T) {
 	//Ensure that the MathML is in the result
 	assert.True(t, strings.Contains(w.Body.String(), "><mfrac><mn>2</mn><mi>x</mi></mfrac></math> in Result"))
 }
+
+func Test_RadioInput(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Func</Title>
+        <Task>
+            <Input id="val1" type="radio">
+                <Label>Auswahl:</Label>
+                <Option value="a">Option A</Option>
+                <Option value="b">Option B</Option>
+                <Validator>
+                    <Expression>answer.val1="b"</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	h := CreateTask(lectures, states)
+
+	r := httptest.NewRequest("POST", "/task/ET1/0/0", nil)
+	r.Form = map[string][]string{"input_val1": {"b"}}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, `value="b" checked`))
+	assert.True(t, strings.Contains(body, "Richtig!"))
+}
+
+func Test_TruthTableInput(t *testing.T) {
+	const lecture = `<Lecture id="DT1">
+    <Title>Digitaltechnik</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task>
+            <Input id="Y" type="truthTable" vars="A,B">
+                <Label>Y=A xor B</Label>
+                <Validator>
+                    <Expression>cmpBool("A xor B",answer.Y,["A","B"])</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	h := CreateTask(lectures, states)
+
+	post := func(cells ...string) string {
+		r := httptest.NewRequest("POST", "/task/DT1/0/0", nil)
+		r.Form = map[string][]string{}
+		for i, c := range cells {
+			r.Form.Set(fmt.Sprintf("input_Y_%d", i), c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	body := post("0", "1", "1", "0")
+	assert.Contains(t, body, `name="input_Y_1" value="1"`)
+	assert.Contains(t, body, "Richtig!")
+
+	body = post("0", "1", "", "0")
+	assert.Contains(t, body, `name="input_Y_2" value=""`)
+	assert.Contains(t, body, "Die Wahrheitstabelle ist nicht vollständig ausgefüllt!")
+	assert.NotContains(t, body, "Richtig!")
+}
+
+func Test_MatrixInput(t *testing.T) {
+	const lecture = `<Lecture id="MA1">
+    <Title>Mathematik</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Matrizen</Title>
+        <Task>
+            <Input id="M" type="matrix" rows="2" cols="2">
+                <Label>Inverse von ((2,0),(0,4))</Label>
+                <Validator>
+                    <Expression>cmpMatrix(inv([[2,0],[0,4]]),answer.M,1,false)</Expression>
+                </Validator>
+            </Input>
+            <Test M="0.5,0;0,0.25" ok="yes"/>
+            <Test M="1/2,0;0,1/4" ok="yes"/>
+            <Test M="2,0;0,4" ok="no"/>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	h := CreateTask(lectures, states)
+
+	post := func(cells ...string) string {
+		r := httptest.NewRequest("POST", "/task/MA1/0/0", nil)
+		r.Form = map[string][]string{}
+		for i, c := range cells {
+			r.Form.Set(fmt.Sprintf("input_M_%d_%d", i/2, i%2), c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	body := post("1/2", "0", "0", "0.25")
+	assert.Contains(t, body, `name="input_M_0_0" value="1/2"`)
+	assert.Contains(t, body, `name="input_M_1_1" value="0.25"`)
+	assert.Contains(t, body, "Richtig!")
+
+	body = post("0.5", "0", "", "0.25")
+	assert.Contains(t, body, `name="input_M_1_0" value=""`)
+	assert.Contains(t, body, "Die Matrix ist nicht vollständig ausgefüllt!")
+	assert.NotContains(t, body, "Richtig!")
+}
+
+func Test_MaxAttempts(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Func</Title>
+        <Task maxAttempts="2">
+            <Input id="val1" type="checkbox">
+                <Label>Richtig?</Label>
+                <Validator>
+                    <Expression>answer.val1</Expression>
+                    <Explanation>Ist richtig.</Explanation>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	h := CreateTask(lectures, states)
+	ses := &session.Session{}
+
+	post := func(answer string) string {
+		r := httptest.NewRequest("POST", "/task/ET1/0/0", nil)
+		r.Form = map[string][]string{"input_val1": {answer}}
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	body := post("")
+	assert.True(t, strings.Contains(body, "Noch 1 Versuch"))
+	body = post("")
+	assert.True(t, strings.Contains(body, "Die maximale Anzahl von Versuchen ist erreicht."))
+	body = post("on")
+	assert.False(t, strings.Contains(body, "Richtig!"))
+	assert.Equal(t, 2, ses.FailedAttempts(lec.Chapter[0].Task[0]))
+}
+
+func Test_NextHint(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Func</Title>
+        <Task>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Hint after="5">Erster Hinweis</Hint>
+                <Validator>
+                    <Expression>cmpValues(1,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	h := CreateTask(lectures, states)
+	ses := &session.Session{}
+
+	r := httptest.NewRequest("POST", "/task/ET1/0/0", nil)
+	r.Form = map[string][]string{"input_val1": {"2"}, "nextHint": {"x"}}
+	r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "Erster Hinweis"))
+	assert.False(t, strings.Contains(body, `name="nextHint"`))
+	assert.True(t, strings.Contains(body, `value="2"`))
+	assert.Equal(t, 1, ses.HintsRequested(lec.Chapter[0].Task[0]))
+	assert.Equal(t, 0, ses.FailedAttempts(lec.Chapter[0].Task[0]))
+}
+
+func Test_PoolIsTaskAvail(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter stepByStep="true">
+        <Title>Func</Title>
+        <Pool draw="2">
+            <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+            <Task><Name>B</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(2,answer.v,1)</Expression></Validator></Input></Task>
+            <Task><Name>C</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(3,answer.v,1)</Expression></Validator></Input></Task>
+            <Task><Name>D</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(4,answer.v,1)</Expression></Validator></Input></Task>
+        </Pool>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	ses := &session.Session{}
+	state := data.LectureState{}
+	chapter := lec.Chapter[0]
+	drawn := chapter.SelectedTasks(selection(&state, ses))
+	assert.Equal(t, 2, len(drawn))
+
+	for _, task := range chapter.Task {
+		switch task {
+		case drawn[0]:
+			assert.True(t, IsTaskAvail(task, &state, ses))
+		default:
+			assert.False(t, IsTaskAvail(task, &state, ses))
+		}
+	}
+
+	ses.TaskCompleted(drawn[0])
+	assert.True(t, IsTaskAvail(drawn[1], &state, ses))
+	assert.Equal(t, 1, completedTasks(chapter, ses, &state))
+
+	state.ShowWholePool = true
+	assert.Equal(t, 4, chapter.Tasks(selection(&state, ses)))
+}
+
+func Test_Exam(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter examMinutes="30">
+        <Title>Prüfung</Title>
+        <Task>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.val1,1)</Expression>
+                    <Explanation>Der Wert ist eins.</Explanation>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := data.NewLectureStates(filepath.Join(t.TempDir(), "state"))
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	chapter := CreateChapter(lectures, states)
+	taskHandler := CreateTask(lectures, states)
+	ses := &session.Session{}
+	exam := lec.Chapter[0]
+	task := exam.Task[0]
+
+	request := func(h http.Handler, method, path string, form map[string][]string) string {
+		r := httptest.NewRequest(method, path, nil)
+		r.Form = form
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	state := states.Get(lec.Id)
+	assert.False(t, IsTaskAvail(task, &state, ses))
+	body := request(chapter, "GET", "/chapter/ET1/0", nil)
+	assert.Contains(t, body, "Die Prüfung ist noch nicht freigegeben.")
+
+	// the exam can not be started before it is opened
+	request(chapter, "POST", "/chapter/ET1/0", map[string][]string{"startExam": {"true"}})
+	assert.False(t, IsTaskAvail(task, &state, ses))
+
+	state.SetExam(exam, data.ExamState{Start: time.Now().Add(-time.Minute).Unix()})
+	assert.NoError(t, states.SetState(lec.Id, state))
+	body = request(chapter, "POST", "/chapter/ET1/0", map[string][]string{"startExam": {"true"}})
+	assert.Contains(t, body, "Prüfung abgeben")
+	assert.True(t, IsTaskAvail(task, &state, ses))
+
+	// the answer is stored without any feedback
+	body = request(taskHandler, "POST", "/task/ET1/0/0", map[string][]string{"input_val1": {"1"}})
+	assert.Contains(t, body, "Die Antwort ist gespeichert.")
+	assert.NotContains(t, body, "Richtig!")
+	assert.NotContains(t, body, `name="showResult"`)
+	assert.Equal(t, 0.0, ses.Score(task))
+
+	// a reload shows the stored answer
+	body = request(taskHandler, "GET", "/task/ET1/0/0", nil)
+	assert.Contains(t, body, `value="1"`)
+
+	body = request(chapter, "POST", "/chapter/ET1/0", map[string][]string{"submitExam": {"true"}})
+	assert.Contains(t, body, "Die Prüfung ist abgegeben.")
+	assert.Equal(t, 1.0, ses.Score(task))
+
+	// no more changes after the submission, and no results before the release
+	body = request(taskHandler, "POST", "/task/ET1/0/0", map[string][]string{"input_val1": {"2"}})
+	assert.Contains(t, body, "Die Ergebnisse werden nach der Freigabe angezeigt.")
+	assert.Contains(t, body, `value="1"`)
+	assert.NotContains(t, body, "Richtig!")
+	assert.Equal(t, 0.0, chapterPoints(exam, ses, &state))
+
+	state.SetExam(exam, data.ExamState{Start: state.Exam(exam).Start, Released: true})
+	assert.NoError(t, states.SetState(lec.Id, state))
+	body = request(taskHandler, "GET", "/task/ET1/0/0", nil)
+	assert.Contains(t, body, "Richtig!")
+	assert.Equal(t, 1.0, chapterPoints(exam, ses, &state))
+}
+
+func Test_ChapterSchedule(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Woche 1</Title>
+        <Task>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>cmpValues(1,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+    <Chapter>
+        <Title>Woche 2</Title>
+        <Task>
+            <Input id="val1" type="text">
+                <Label>Wert:</Label>
+                <Validator>
+                    <Expression>cmpValues(2,answer.val1,1)</Expression>
+                </Validator>
+            </Input>
+        </Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := data.NewLectureStates(filepath.Join(t.TempDir(), "state"))
+	state := states.Get(lec.Id)
+	state.SetSchedule(lec.Chapter[0], data.ChapterSchedule{SolutionsFrom: time.Now().Add(-time.Hour).Unix()})
+	state.SetSchedule(lec.Chapter[1], data.ChapterSchedule{VisibleFrom: time.Now().Add(time.Hour).Unix()})
+	assert.NoError(t, states.SetState(lec.Id, state))
+
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+
+	request := func(h http.Handler, path string, ses *session.Session) string {
+		r := httptest.NewRequest("GET", path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	user := &session.Session{}
+	body := request(CreateLecture(lectures, states), "/lecture/ET1", user)
+	assert.Contains(t, body, "Woche 1")
+	assert.NotContains(t, body, "Woche 2")
+	assert.Panics(t, func() { request(CreateChapter(lectures, states), "/chapter/ET1/1", user) })
+	assert.False(t, IsTaskAvail(lec.Chapter[1].Task[0], &state, user))
+
+	// the solutions of the first chapter are released
+	body = request(CreateTask(lectures, states), "/task/ET1/0/0", user)
+	assert.Contains(t, body, `name="showResult"`)
+
+	admin := session.New(t.TempDir(), lectures).Create("admin", true, httptest.NewRecorder())
+	body = request(CreateLecture(lectures, states), "/lecture/ET1", admin)
+	assert.Contains(t, body, "Woche 2")
+	assert.Contains(t, body, `title="verborgen"`)
+	assert.True(t, IsTaskAvail(lec.Chapter[1].Task[0], &state, admin))
+}
+
+func Test_ChapterRequires(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+        <Task><Name>B</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(2,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+    <Chapter requires="1:50">
+        <Title>Netzwerke</Title>
+        <Task><Name>C</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(3,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	ses := &session.Session{}
+	state := data.LectureState{}
+
+	request := func(h http.Handler, path string) string {
+		r := httptest.NewRequest("GET", path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	locked := "Zuerst müssen 50% der Fragen in &#39;Grundlagen&#39; gelöst werden."
+	body := request(CreateLecture(lectures, states), "/lecture/ET1")
+	assert.Contains(t, body, locked)
+	assert.False(t, IsTaskAvail(lec.Chapter[1].Task[0], &state, ses))
+	assert.Panics(t, func() { request(CreateChapter(lectures, states), "/chapter/ET1/1") })
+
+	ses.TaskCompleted(lec.Chapter[0].Task[0])
+	body = request(CreateLecture(lectures, states), "/lecture/ET1")
+	assert.NotContains(t, body, locked)
+	assert.True(t, IsTaskAvail(lec.Chapter[1].Task[0], &state, ses))
+	request(CreateChapter(lectures, states), "/chapter/ET1/1")
+}
+
+func Test_Practice(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Tag>Kirchhoff</Tag><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+        <Task><Name>B</Name><Tag>Ohm</Tag><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(2,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+    <Chapter>
+        <Title>Netzwerke</Title>
+        <Task><Name>C</Name><Tag>Kirchhoff</Tag><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(3,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	ses := &session.Session{}
+
+	request := func(h http.Handler, method, path string, form map[string][]string) string {
+		r := httptest.NewRequest(method, path, nil)
+		r.Form = form
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	body := request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/practice/ET1/Kirchhoff/"`)
+	assert.Contains(t, body, "Kirchhoff&nbsp;(2)")
+	assert.Contains(t, body, "Ohm&nbsp;(1)")
+
+	body = request(CreatePractice(lectures, states), "GET", "/practice/ET1/Kirchhoff/", nil)
+	assert.Contains(t, body, "Frage 1: A")
+	assert.Contains(t, body, "Frage 1: C")
+	assert.NotContains(t, body, "Frage 2: B")
+	assert.Contains(t, body, `href="/task/ET1/0/0/?tag=Kirchhoff"`)
+
+	// solving a task leads to the next task with the same tag
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/0/", map[string][]string{"input_v": {"1"}, "tag": {"Kirchhoff"}})
+	assert.Contains(t, body, "Richtig!")
+	assert.Contains(t, body, `href="/task/ET1/1/0/?tag=Kirchhoff"`)
+
+	body = request(CreatePractice(lectures, states), "GET", "/practice/ET1/Kirchhoff/", nil)
+	assert.Contains(t, body, `href="/task/ET1/1/0/?tag=Kirchhoff">Nächste offene Frage`)
+
+	assert.Panics(t, func() { request(CreatePractice(lectures, states), "GET", "/practice/ET1/Unknown/", nil) })
+}
+
+func Test_Review(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	ses := &session.Session{}
+
+	request := func(h http.Handler, method, path string, form map[string][]string) string {
+		r := httptest.NewRequest(method, path, nil)
+		r.Form = form
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	body := request(CreateReview(lectures, states), "GET", "/review/ET1/", nil)
+	assert.Contains(t, body, "Keine Fragen zur Wiederholung fällig.")
+
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/0/", map[string][]string{"input_v": {"1"}})
+	assert.Contains(t, body, "Richtig!")
+
+	// the task is reviewed one day after its completion
+	task, err := lec.GetTask(data.ChapterNum{0}, 0)
+	assert.NoError(t, err)
+	due, ok := ses.ReviewDue(task)
+	assert.True(t, ok)
+	assert.True(t, due.After(time.Now()))
+	body = request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.NotContains(t, body, "/review/ET1/")
+
+	// a wrong answer in review mode does not touch the completion
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/0/", map[string][]string{"input_v": {"2"}, "review": {"true"}})
+	assert.Contains(t, body, `<input type="hidden" name="review" value="true">`)
+	assert.Contains(t, body, `href="/review/ET1/"`)
+	assert.NotContains(t, body, "Richtig!")
+	assert.True(t, ses.IsTaskCompleted(task))
+	assert.Equal(t, 0, ses.FailedAttempts(task))
+	due2, _ := ses.ReviewDue(task)
+	assert.Equal(t, due, due2)
+}
+
+type fixedRecommender struct {
+	task *data.Task
+}
+
+func (f fixedRecommender) Recommend(*data.Lecture, *data.Task, *data.LectureState, *session.Session) *data.Task {
+	return f.task
+}
+
+func Test_Recommend(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Tag>Ohm</Tag><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+        <Task><Name>B</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(2,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+    <Chapter id="net">
+        <Title>Netzwerke</Title>
+        <Task><Name>C</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(3,answer.v,1)</Expression></Validator></Input></Task>
+        <Task><Name>D</Name><Tag>Ohm</Tag><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(4,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+    <Chapter requires="net">
+        <Title>Wechselstrom</Title>
+        <Task><Name>E</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(5,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+	ses := &session.Session{}
+
+	request := func(h http.Handler, method, path string, form map[string][]string) string {
+		r := httptest.NewRequest(method, path, nil)
+		r.Form = form
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	// tasks needed to unlock a chapter are preferred
+	body := request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/task/ET1/1/0/">Empfohlen als Nächstes: Frage 1: C`)
+
+	// the failed task A is recommended
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/0/", map[string][]string{"input_v": {"0"}})
+	assert.NotContains(t, body, "Empfohlen als Nächstes")
+	body = request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/task/ET1/0/0/">Empfohlen als Nächstes: Frage 1: A`)
+
+	// the chapter worked on last is continued
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/0/", map[string][]string{"input_v": {"1"}})
+	assert.Contains(t, body, `href="/task/ET1/0/1/">Empfohlen als Nächstes: Frage 2: B`)
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/0/1/", map[string][]string{"input_v": {"2"}})
+	assert.Contains(t, body, `href="/task/ET1/1/0/">Empfohlen als Nächstes: Frage 1: C`)
+
+	// tasks of the locked chapter are not recommended
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/1/0/", map[string][]string{"input_v": {"3"}})
+	assert.Contains(t, body, `href="/task/ET1/1/1/">Empfohlen als Nächstes: Frage 2: D`)
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/1/1/", map[string][]string{"input_v": {"4"}})
+	assert.Contains(t, body, `href="/task/ET1/2/0/">Empfohlen als Nächstes: Frage 1: E`)
+
+	// the strategy can be replaced
+	task, err := lec.GetTask(data.ChapterNum{1}, 0)
+	assert.NoError(t, err)
+	defer func(r Recommender) { DefaultRecommender = r }(DefaultRecommender)
+	DefaultRecommender = fixedRecommender{task: task}
+	body = request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/task/ET1/1/0/?review=true">Empfohlen als Nächstes: Frage 1: C`, "completed tasks are reviewed")
+}
+
+func Test_RecommendReview(t *testing.T) {
+	const lecture = `<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+    <Chapter>
+        <Title>Netzwerke</Title>
+        <Task><Name>B</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(2,answer.v,1)</Expression></Validator></Input></Task>
+        <Task><Name>C</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(3,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`
+
+	lec, err := readLectureToTest(lecture)
+	assert.NoError(t, err)
+	a, err := lec.GetTask(data.ChapterNum{0}, 0)
+	assert.NoError(t, err)
+	b, err := lec.GetTask(data.ChapterNum{1}, 0)
+	assert.NoError(t, err)
+
+	states := &data.LectureStates{}
+	lectures := &data.Lectures{}
+	lectures.Insert(lec)
+
+	// A was completed just now, B two days ago, so only the review of B is due
+	folder := t.TempDir()
+	var buf bytes.Buffer
+	assert.NoError(t, serialize.New().Write(&buf, map[data.LectureId]map[data.TaskId]int64{
+		"ET1": {a.TID(): time.Now().Unix(), b.TID(): time.Now().Add(-48 * time.Hour).Unix()},
+	}))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "token"), buf.Bytes(), 0644))
+	ses := session.New(folder, lectures).Create("token", false, httptest.NewRecorder())
+	assert.True(t, ses.IsReviewDue(b, time.Now()))
+
+	request := func(h http.Handler, method, path string, form map[string][]string) string {
+		r := httptest.NewRequest(method, path, nil)
+		r.Form = form
+		r = r.WithContext(context.WithValue(r.Context(), session.Key, ses))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+
+	// B is not reviewed as long as its chapter is not completed
+	c, err := lec.GetTask(data.ChapterNum{1}, 1)
+	assert.NoError(t, err)
+	state := states.Get(lec.Id)
+	assert.Nil(t, HistoryRecommender{}.Recommend(lec, c, &state, ses))
+
+	body := request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/task/ET1/1/1/">Empfohlen als Nächstes: Frage 2: C`)
+	request(CreateTask(lectures, states), "POST", "/task/ET1/1/1/", map[string][]string{"input_v": {"3"}})
+
+	// the due review of B is opened in the review mode
+	body = request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.Contains(t, body, `href="/task/ET1/1/0/?review=true">Empfohlen als Nächstes: Frage 1: B`)
+
+	body = request(CreateTask(lectures, states), "POST", "/task/ET1/1/0/", map[string][]string{"input_v": {"0"}, "review": {"true"}})
+	assert.NotContains(t, body, "Richtig!")
+	assert.Equal(t, 0, ses.FailedAttempts(b), "a review does not touch the statistics")
+	assert.False(t, ses.IsReviewDue(b, time.Now()))
+
+	body = request(CreateLecture(lectures, states), "GET", "/lecture/ET1", nil)
+	assert.NotContains(t, body, "Empfohlen als Nächstes")
+}
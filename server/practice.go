@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server/session"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// practiceTasks returns the tasks with the given tag which can be practiced
+// by the user. Tasks of exams and tasks not visible to the user are omitted.
+func practiceTasks(lecture *data.Lecture, tag string, state *data.LectureState, ses *session.Session) []*data.Task {
+	sel := selection(state, ses)
+	admin := ses != nil && ses.IsAdmin()
+	var tasks []*data.Task
+	for _, task := range lecture.TaggedTasks(tag) {
+		ch := task.Chapter()
+		if !task.IsSelected(sel) || !isChapterVisible(ch, state, ses) || (ch.IsExam() && !admin) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// nextPracticeTask returns the next available task with the given tag
+// which is not completed yet. The search starts behind the given task.
+// If task is nil, the search starts at the first task.
+func nextPracticeTask(tasks []*data.Task, task *data.Task, state *data.LectureState, ses *session.Session) *data.Task {
+	start := 0
+	for i, t := range tasks {
+		if t == task {
+			start = i + 1
+			break
+		}
+	}
+	for i := range tasks {
+		t := tasks[(start+i)%len(tasks)]
+		if t != task && !ses.IsTaskCompleted(t) && IsTaskAvail(t, state, ses) {
+			return t
+		}
+	}
+	return nil
+}
+
+// practicePath returns the path of a task opened from the practice view
+func practicePath(task *data.Task, tag string) string {
+	ch := task.Chapter()
+	return fmt.Sprintf("/task/%s/%v/%d/?tag=%s", ch.Lecture().Id, ch.Num(), task.Num(), url.QueryEscape(tag))
+}
+
+// tagCloudEntry is a tag shown on the lecture page
+type tagCloudEntry struct {
+	Tag   string
+	Count int
+	// Size is the font size in percent
+	Size int
+}
+
+// TagCloud returns the tags of the lecture together with the number
+// of tasks the user can practice.
+func (cd lectureData) TagCloud() []tagCloudEntry {
+	var entries []tagCloudEntry
+	maxCount := 0
+	for _, tc := range cd.Lecture.Tags() {
+		n := len(practiceTasks(cd.Lecture, tc.Tag, &cd.state, cd.session))
+		if n > 0 {
+			entries = append(entries, tagCloudEntry{Tag: tc.Tag, Count: n})
+			maxCount = max(maxCount, n)
+		}
+	}
+	for i := range entries {
+		entries[i].Size = 80 + 70*entries[i].Count/maxCount
+	}
+	return entries
+}
+
+var practiceTemp = Templates.Lookup("practice.html")
+
+type practiceData struct {
+	Lecture *data.Lecture
+	Tag     string
+	Tasks   []*data.Task
+	session *session.Session
+	state   data.LectureState
+}
+
+// Completed returns true if the task is completed
+func (pd practiceData) Completed(task *data.Task) bool {
+	return pd.session != nil && pd.session.IsTaskCompleted(task) && isResultVisible(task, &pd.state, pd.session)
+}
+
+// IsAvail returns true if the task is available to the user
+func (pd practiceData) IsAvail(task *data.Task) bool {
+	return pd.session != nil && IsTaskAvail(task, &pd.state, pd.session)
+}
+
+// Path returns the path of the given task
+func (pd practiceData) Path(task *data.Task) string {
+	return practicePath(task, pd.Tag)
+}
+
+// Next returns the path of the first available task which is not completed yet
+func (pd practiceData) Next() string {
+	if pd.session == nil {
+		return ""
+	}
+	if t := nextPracticeTask(pd.Tasks, nil, &pd.state, pd.session); t != nil {
+		return practicePath(t, pd.Tag)
+	}
+	return ""
+}
+
+// CreatePractice creates the handler which shows all tasks with a given tag
+func CreatePractice(lectures *data.Lectures, states *data.LectureStates) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag, next := getStrFromPath(r.URL.Path)
+		l, _ := getLectureFromPath(next)
+		lecture, err := lectures.GetLecture(l)
+		if err != nil {
+			panic(err)
+		}
+
+		ses, _ := r.Context().Value(session.Key).(*session.Session)
+		state := states.Get(lecture.Id)
+
+		tasks := practiceTasks(lecture, tag, &state, ses)
+		if len(tasks) == 0 {
+			panic(fmt.Sprintf("Keine Fragen zum Thema '%s' gefunden!", tag))
+		}
+
+		err = practiceTemp.Execute(w, practiceData{Lecture: lecture, Tag: tag, Tasks: tasks, session: ses, state: state})
+		if err != nil {
+			log.Println(err)
+		}
+	})
+}
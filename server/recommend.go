@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server/session"
+	"time"
+)
+
+// Recommender selects the task a user should work on next
+type Recommender interface {
+	// Recommend returns the recommended task or nil if there is nothing to recommend.
+	// The task the user has worked on last is given in last, which may be nil.
+	// Only tasks available to the user may be returned.
+	Recommend(lecture *data.Lecture, last *data.Task, state *data.LectureState, ses *session.Session) *data.Task
+}
+
+// DefaultRecommender is the recommender used by the task and lecture pages.
+// It can be replaced to try other strategies.
+var DefaultRecommender Recommender = HistoryRecommender{}
+
+// recommend returns the task recommended by the DefaultRecommender
+func recommend(lecture *data.Lecture, last *data.Task, state *data.LectureState, ses *session.Session) *data.Task {
+	if ses == nil || DefaultRecommender == nil {
+		return nil
+	}
+	return DefaultRecommender.Recommend(lecture, last, state, ses)
+}
+
+// HistoryRecommender recommends a task based on the history of the user.
+// Open tasks are preferred, especially those which were failed before,
+// which share a tag with a failed task, which belong to the chapter worked
+// on last, or which are needed to unlock a further chapter.
+// Completed tasks are recommended if they are part of the review queue.
+// The longer the review is overdue, the higher the task is rated.
+type HistoryRecommender struct{}
+
+const (
+	ratingOpen         = 10
+	ratingFailed       = 3
+	ratingMaxFailed    = 3
+	ratingWeakTag      = 5
+	ratingSameChapter  = 5
+	ratingUnlocks      = 3
+	ratingReview       = 4
+	ratingMaxReviewDay = 5
+)
+
+// Recommend implements the Recommender interface
+func (h HistoryRecommender) Recommend(lecture *data.Lecture, last *data.Task, state *data.LectureState, ses *session.Session) *data.Task {
+	now := time.Now()
+	sel := selection(state, ses)
+
+	weakTags := map[string]bool{}
+	for task := range lecture.Iter {
+		if ses.FailedAttempts(task) > 0 && !ses.IsTaskCompleted(task) {
+			for _, t := range task.Tag {
+				weakTags[t] = true
+			}
+		}
+	}
+
+	reviews := map[*data.Task]bool{}
+	for _, task := range reviewTasks(lecture, state, ses, now) {
+		reviews[task] = true
+	}
+
+	unlocks := map[*data.Chapter]bool{}
+	for _, ch := range lecture.AllChapters() {
+		if lockReason(ch, state, ses) != "" {
+			for _, r := range ch.Requirements() {
+				unlocks[r.Chapter] = true
+			}
+		}
+	}
+
+	var best *data.Task
+	bestRating := 0
+	for _, ch := range lecture.AllChapters() {
+		if ch.IsExam() {
+			continue
+		}
+		for _, task := range ch.SelectedTasks(sel) {
+			if task == last || !IsTaskAvail(task, state, ses) {
+				continue
+			}
+			rating := h.rate(task, last, ses, weakTags, unlocks, reviews, now)
+			if rating > bestRating {
+				best = task
+				bestRating = rating
+			}
+		}
+	}
+	return best
+}
+
+// rate returns the rating of a task, zero if the task should not be recommended
+func (h HistoryRecommender) rate(task, last *data.Task, ses *session.Session, weakTags map[string]bool, unlocks map[*data.Chapter]bool, reviews map[*data.Task]bool, now time.Time) int {
+	if ses.IsTaskCompleted(task) {
+		if !reviews[task] {
+			return 0
+		}
+		due, _ := ses.ReviewDue(task)
+		return ratingReview + min(int(now.Sub(due).Hours()/24), ratingMaxReviewDay)
+	}
+
+	rating := ratingOpen + ratingFailed*min(ses.FailedAttempts(task), ratingMaxFailed)
+	for _, t := range task.Tag {
+		if weakTags[t] {
+			rating += ratingWeakTag
+			break
+		}
+	}
+	if last != nil && last.Chapter() == task.Chapter() {
+		rating += ratingSameChapter
+	}
+	for c := task.Chapter(); c != nil; c = c.ParentChapter {
+		if unlocks[c] {
+			rating += ratingUnlocks
+			break
+		}
+	}
+	return rating
+}
+
+// recommendedPath returns the path of a recommended task. A completed task
+// is only recommended for review, so it is opened in the review mode.
+func recommendedPath(task *data.Task, ses *session.Session) string {
+	if ses != nil && ses.IsTaskCompleted(task) {
+		return reviewPath(task)
+	}
+	ch := task.Chapter()
+	return fmt.Sprintf("/task/%s/%v/%d/", ch.Lecture().Id, ch.Num(), task.Num())
+}
+
+// Recommended returns the task recommended to the user
+func (cd lectureData) Recommended() *data.Task {
+	return recommend(cd.Lecture, nil, &cd.state, cd.session)
+}
+
+// RecommendedPath returns the path of the recommended task
+func (cd lectureData) RecommendedPath(task *data.Task) string {
+	return recommendedPath(task, cd.session)
+}
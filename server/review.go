@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"github.com/hneemann/quiz/data"
+	"github.com/hneemann/quiz/server/session"
+	"log"
+	"net/http"
+	"time"
+)
+
+// reviewTasks returns the tasks of the completed chapters whose review is due.
+// Exams, hidden and locked chapters are omitted.
+func reviewTasks(lecture *data.Lecture, state *data.LectureState, ses *session.Session, now time.Time) []*data.Task {
+	if ses == nil {
+		return nil
+	}
+	sel := selection(state, ses)
+	var tasks []*data.Task
+	for _, ch := range lecture.AllChapters() {
+		if len(ch.Chapter) > 0 || ch.IsExam() || !isChapterVisible(ch, state, ses) || lockReason(ch, state, ses) != "" {
+			continue
+		}
+		if n := ch.Tasks(sel); n == 0 || completedTasks(ch, ses, state) < n {
+			continue
+		}
+		for _, task := range ch.SelectedTasks(sel) {
+			if ses.IsReviewDue(task, now) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks
+}
+
+// nextReviewTask returns the next task whose review is due.
+// The search starts behind the given task.
+func nextReviewTask(lecture *data.Lecture, task *data.Task, state *data.LectureState, ses *session.Session) *data.Task {
+	tasks := reviewTasks(lecture, state, ses, time.Now())
+	start := 0
+	for i, t := range tasks {
+		if t == task {
+			start = i + 1
+			break
+		}
+	}
+	for i := range tasks {
+		if t := tasks[(start+i)%len(tasks)]; t != task {
+			return t
+		}
+	}
+	return nil
+}
+
+// reviewPath returns the path of a task opened from the review view
+func reviewPath(task *data.Task) string {
+	ch := task.Chapter()
+	return fmt.Sprintf("/task/%s/%v/%d/?review=true", ch.Lecture().Id, ch.Num(), task.Num())
+}
+
+// ReviewCount returns the number of tasks whose review is due
+func (cd lectureData) ReviewCount() int {
+	return len(reviewTasks(cd.Lecture, &cd.state, cd.session, time.Now()))
+}
+
+var reviewTemp = Templates.Lookup("review.html")
+
+type reviewData struct {
+	Lecture *data.Lecture
+	Tasks   []*data.Task
+	session *session.Session
+}
+
+// Path returns the path of the given task
+func (rd reviewData) Path(task *data.Task) string {
+	return reviewPath(task)
+}
+
+// Due returns the date the review of the task was due
+func (rd reviewData) Due(task *data.Task) string {
+	due, _ := rd.session.ReviewDue(task)
+	return due.Format("02.01.2006")
+}
+
+// CreateReview creates the handler which shows the tasks whose review is due.
+// Only the tasks of completed chapters are reviewed.
+func CreateReview(lectures *data.Lectures, states *data.LectureStates) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l, _ := getLectureFromPath(r.URL.Path)
+		lecture, err := lectures.GetLecture(l)
+		if err != nil {
+			panic(err)
+		}
+
+		ses, _ := r.Context().Value(session.Key).(*session.Session)
+		state := states.Get(lecture.Id)
+
+		tasks := reviewTasks(lecture, &state, ses, time.Now())
+		err = reviewTemp.Execute(w, reviewData{Lecture: lecture, Tasks: tasks, session: ses})
+		if err != nil {
+			log.Println(err)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package session
+
+import (
+	"github.com/hneemann/quiz/data"
+	"maps"
+	"time"
+)
+
+// ExamAttempt contains the exam data of a single user
+type ExamAttempt struct {
+	// Started is the unix time the user started the exam
+	Started int64
+	// Submitted is the unix time the user submitted the exam
+	Submitted int64
+	// Answers contains the answers given by the user
+	Answers map[data.TaskId]map[data.InputId]string
+}
+
+// Deadline returns the time the exam ends for the user
+func (e ExamAttempt) Deadline(c *data.Chapter) time.Time {
+	return time.Unix(e.Started, 0).Add(c.ExamDuration())
+}
+
+// IsRunning returns true if the user is working on the exam
+func (e ExamAttempt) IsRunning(c *data.Chapter, now time.Time) bool {
+	return e.Started > 0 && e.Submitted == 0 && now.Before(e.Deadline(c))
+}
+
+// IsFinished returns true if the exam was submitted or the time is up
+func (e ExamAttempt) IsFinished(c *data.Chapter, now time.Time) bool {
+	return e.Submitted > 0 || (e.Started > 0 && !now.Before(e.Deadline(c)))
+}
+
+// Exam returns the exam data of the given chapter
+func (s *Session) Exam(c *data.Chapter) ExamAttempt {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e := s.exams[c.Lecture().Id][c.StateId()]
+	e.Answers = maps.Clone(e.Answers)
+	return e
+}
+
+// StartExam starts the exam. If the exam was already started, nothing happens.
+func (s *Session) StartExam(c *data.Chapter) {
+	s.modifyExam(c, func(e *ExamAttempt) {
+		if e.Started == 0 {
+			e.Started = time.Now().Unix()
+		}
+	})
+}
+
+// ExamAnswers stores the answers given in an exam task
+func (s *Session) ExamAnswers(task *data.Task, answers map[data.InputId]string) {
+	s.modifyExam(task.Chapter(), func(e *ExamAttempt) {
+		if e.Answers == nil {
+			e.Answers = map[data.TaskId]map[data.InputId]string{}
+		}
+		e.Answers[task.TID()] = answers
+	})
+}
+
+// SubmitExam marks the exam as submitted.
+// Returns false if the exam was already submitted before.
+func (s *Session) SubmitExam(c *data.Chapter) bool {
+	submitted := false
+	s.modifyExam(c, func(e *ExamAttempt) {
+		if e.Submitted == 0 {
+			now := time.Now()
+			if deadline := e.Deadline(c); now.After(deadline) {
+				now = deadline
+			}
+			e.Submitted = now.Unix()
+			submitted = true
+		}
+	})
+	return submitted
+}
+
+func (s *Session) modifyExam(c *data.Chapter, modify func(e *ExamAttempt)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.exams == nil {
+		s.exams = make(map[data.LectureId]map[string]ExamAttempt)
+	}
+
+	lectureId := c.Lecture().Id
+	lmap, ok := s.exams[lectureId]
+	if !ok {
+		lmap = make(map[string]ExamAttempt)
+		s.exams[lectureId] = lmap
+	}
+
+	e := lmap[c.StateId()]
+	modify(&e)
+	lmap[c.StateId()] = e
+	s.dataModified = true
+}
+
+// ExamStats contains the exam data of a single user
+type ExamStats struct {
+	User string
+	ExamAttempt
+}
+
+// ExamStats returns the exam data of all users who started the given exam.
+func (s *Sessions) ExamStats(c *data.Chapter) ([]ExamStats, error) {
+	var found []ExamStats
+	err := s.scan(func(token string, se *Session) {
+		if e, ok := se.exams[c.Lecture().Id][c.StateId()]; ok {
+			found = append(found, ExamStats{User: token, ExamAttempt: e})
+		}
+	})
+	return found, err
+}
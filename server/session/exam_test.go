@@ -0,0 +1,33 @@
+package session
+
+import (
+	"encoding/xml"
+	"github.com/hneemann/quiz/data"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSession_cleanupExams(t *testing.T) {
+	var l data.Lecture
+	err := xml.Unmarshal([]byte(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter id="exam" examMinutes="30">
+        <Title>Prüfung</Title>
+        <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`), &l)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Init())
+	lectures := &data.Lectures{}
+	lectures.Insert(&l)
+
+	// attempt stored before the chapter got its id
+	s := &Session{exams: map[data.LectureId]map[string]ExamAttempt{"ET1": {"0": {Started: 5}}}}
+	s.cleanup(lectures)
+
+	assert.True(t, s.dataModified)
+	assert.Equal(t, int64(5), s.Exam(l.Chapter[0]).Started)
+	assert.NotContains(t, s.exams["ET1"], "0")
+}
@@ -0,0 +1,116 @@
+package session
+
+import (
+	"github.com/hneemann/quiz/data"
+	"math"
+	"time"
+)
+
+const (
+	// firstReviewDelay is the time after completion at which a task is reviewed the first time
+	firstReviewDelay = 24 * time.Hour
+	// initialEase is the ease factor of a task never reviewed before
+	initialEase = 2.5
+	// minEase is the lower limit of the ease factor
+	minEase = 1.3
+	// qualityCorrect is the SM-2 quality used for a correct answer
+	qualityCorrect = 4
+	// qualityWrong is the SM-2 quality used for a wrong answer
+	qualityWrong = 2
+)
+
+// Review contains the review state of a task.
+// The intervals are calculated using the SM-2 algorithm.
+type Review struct {
+	// Due is the unix time the next review is due
+	Due int64
+	// Interval is the current interval in days
+	Interval int
+	// Repetitions is the number of correct reviews in a row
+	Repetitions int
+	// Ease is the factor the interval grows with
+	Ease float64
+}
+
+// next returns the review state after the task was answered at the given time
+func (r Review) next(correct bool, now time.Time) Review {
+	if r.Ease == 0 {
+		r.Ease = initialEase
+	}
+	q := float64(qualityWrong)
+	if correct {
+		q = qualityCorrect
+		switch r.Repetitions {
+		case 0:
+			r.Interval = 1
+		case 1:
+			r.Interval = 6
+		default:
+			r.Interval = int(math.Round(float64(r.Interval) * r.Ease))
+		}
+		r.Repetitions++
+	} else {
+		r.Repetitions = 0
+		r.Interval = 1
+	}
+	r.Ease = max(minEase, r.Ease+0.1-(5-q)*(0.08+(5-q)*0.02))
+	r.Due = now.Add(time.Duration(r.Interval) * 24 * time.Hour).Unix()
+	return r
+}
+
+// ReviewDue returns the time the next review of the task is due.
+// If the task was never completed, false is returned.
+func (s *Session) ReviewDue(task *data.Task) (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.reviewDue(task)
+}
+
+func (s *Session) reviewDue(task *data.Task) (time.Time, bool) {
+	lectureId := task.Chapter().Lecture().Id
+	if r, ok := s.reviews[lectureId][task.TID()]; ok {
+		return time.Unix(r.Due, 0), true
+	}
+	if completed, ok := s.completed[lectureId][task.TID()]; ok {
+		return time.Unix(completed, 0).Add(firstReviewDelay), true
+	}
+	return time.Time{}, false
+}
+
+// IsReviewDue returns true if the task needs to be reviewed at the given time
+func (s *Session) IsReviewDue(task *data.Task, now time.Time) bool {
+	due, ok := s.ReviewDue(task)
+	return ok && !now.Before(due)
+}
+
+// ReviewResult stores the result of a review and schedules the next review.
+// If the task is not due, nothing happens. This way only the first answer
+// given in a review counts.
+// Returns the time of the next review and true if the result was stored.
+func (s *Session) ReviewResult(task *data.Task, correct bool) (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	due, ok := s.reviewDue(task)
+	if !ok || now.Before(due) {
+		return due, false
+	}
+
+	if s.reviews == nil {
+		s.reviews = make(map[data.LectureId]map[data.TaskId]Review)
+	}
+
+	lectureId := task.Chapter().Lecture().Id
+	lmap, ok := s.reviews[lectureId]
+	if !ok {
+		lmap = make(map[data.TaskId]Review)
+		s.reviews[lectureId] = lmap
+	}
+
+	r := lmap[task.TID()].next(correct, now)
+	lmap[task.TID()] = r
+	s.dataModified = true
+	return time.Unix(r.Due, 0), true
+}
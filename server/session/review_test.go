@@ -0,0 +1,78 @@
+package session
+
+import (
+	"encoding/xml"
+	"github.com/hneemann/quiz/data"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReview_next(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	day := int64(24 * 60 * 60)
+	tests := []struct {
+		name    string
+		answers []bool
+		want    Review
+	}{
+		{"first", []bool{true}, Review{Due: now.Unix() + day, Interval: 1, Repetitions: 1, Ease: 2.5}},
+		{"second", []bool{true, true}, Review{Due: now.Unix() + 6*day, Interval: 6, Repetitions: 2, Ease: 2.5}},
+		{"third", []bool{true, true, true}, Review{Due: now.Unix() + 15*day, Interval: 15, Repetitions: 3, Ease: 2.5}},
+		{"wrong", []bool{true, true, false}, Review{Due: now.Unix() + day, Interval: 1, Repetitions: 0, Ease: 2.18}},
+		{"minEase", []bool{false, false, false, false, false}, Review{Due: now.Unix() + day, Interval: 1, Repetitions: 0, Ease: 1.3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := Review{}
+			for _, a := range test.answers {
+				r = r.next(a, now)
+			}
+			assert.Equal(t, test.want.Due, r.Due)
+			assert.Equal(t, test.want.Interval, r.Interval)
+			assert.Equal(t, test.want.Repetitions, r.Repetitions)
+			assert.InDelta(t, test.want.Ease, r.Ease, 1e-6)
+		})
+	}
+}
+
+func TestSession_ReviewResult(t *testing.T) {
+	var l data.Lecture
+	err := xml.Unmarshal([]byte(`<Lecture id="ET1">
+    <Title>Elektrotechnik 1</Title>
+    <Author>Prof. Dr. Helmut Neemann</Author>
+    <AuthorEMail>helmut.neemann@dhbw.de</AuthorEMail>
+    <Chapter>
+        <Title>Grundlagen</Title>
+        <Task><Name>A</Name><Input id="v" type="text"><Label>v:</Label><Validator><Expression>cmpValues(1,answer.v,1)</Expression></Validator></Input></Task>
+	</Chapter>
+</Lecture>`), &l)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Init())
+	task, err := l.GetTask(data.ChapterNum{0}, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	s := &Session{}
+	_, ok := s.ReviewDue(task)
+	assert.False(t, ok)
+
+	s.TaskCompleted(task)
+	assert.False(t, s.IsReviewDue(task, time.Now()))
+	assert.True(t, s.IsReviewDue(task, time.Now().Add(firstReviewDelay)))
+	_, ok = s.ReviewResult(task, true)
+	assert.False(t, ok, "review is not due")
+
+	s.completed["ET1"][task.TID()] = time.Now().Add(-2 * firstReviewDelay).Unix()
+	assert.True(t, s.IsReviewDue(task, time.Now()))
+	due, ok := s.ReviewResult(task, true)
+	assert.True(t, ok)
+	assert.True(t, due.After(time.Now()))
+	assert.False(t, s.IsReviewDue(task, time.Now()))
+	assert.True(t, s.IsTaskCompleted(task))
+
+	// only the first answer counts
+	_, ok = s.ReviewResult(task, false)
+	assert.False(t, ok)
+}
@@ -22,10 +22,29 @@ type Session struct {
 	time         time.Time
 	admin        bool
 	completed    map[data.LectureId]map[data.TaskId]int64
+	scores       map[data.LectureId]map[data.TaskId]float64
+	attempts     map[data.LectureId]map[data.TaskId]int
+	hints        map[data.LectureId]map[data.TaskId]int
+	mistakes     map[data.LectureId]map[data.TaskId]map[string]int
+	exams        map[data.LectureId]map[string]ExamAttempt
+	reviews      map[data.LectureId]map[data.TaskId]Review
 	persistToken string
 	dataModified bool
 }
 
+// persistData is the data stored for each user.
+// New fields are only appended at the end. This way, a file written by
+// an older version can be read up to the missing fields.
+type persistData struct {
+	Completed map[data.LectureId]map[data.TaskId]int64
+	Scores    map[data.LectureId]map[data.TaskId]float64
+	Attempts  map[data.LectureId]map[data.TaskId]int
+	Hints     map[data.LectureId]map[data.TaskId]int
+	Mistakes  map[data.LectureId]map[data.TaskId]map[string]int
+	Exams     map[data.LectureId]map[string]ExamAttempt
+	Reviews   map[data.LectureId]map[data.TaskId]Review
+}
+
 func (s *Session) touch() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -44,6 +63,13 @@ func (s *Session) String() string {
 	}
 }
 
+// PersistToken returns the token identifying the user.
+func (s *Session) PersistToken() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.persistToken
+}
+
 func (s *Session) IsAdmin() bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -70,6 +96,128 @@ func (s *Session) TaskCompleted(task *data.Task) {
 	lmap[task.TID()] = time.Now().Unix()
 }
 
+// TaskScore stores the score of a task, if it is better than the score stored before.
+func (s *Session) TaskScore(task *data.Task, score float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.scores == nil {
+		s.scores = make(map[data.LectureId]map[data.TaskId]float64)
+	}
+
+	lectureId := task.Chapter().Lecture().Id
+	lmap, ok := s.scores[lectureId]
+	if !ok {
+		lmap = make(map[data.TaskId]float64)
+		s.scores[lectureId] = lmap
+	}
+
+	if score > lmap[task.TID()] {
+		s.dataModified = true
+		lmap[task.TID()] = score
+	}
+}
+
+// Score returns the best score reached in the task.
+// A completed task always has the score one.
+func (s *Session) Score(task *data.Task) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lectureId := task.Chapter().Lecture().Id
+	if tmap, ok := s.completed[lectureId]; ok {
+		if _, ok := tmap[task.TID()]; ok {
+			return 1
+		}
+	}
+	if tmap, ok := s.scores[lectureId]; ok {
+		return tmap[task.TID()]
+	}
+	return 0
+}
+
+// TaskFailed counts a failed attempt to solve the task.
+func (s *Session) TaskFailed(task *data.Task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.attempts = s.increment(s.attempts, task)
+}
+
+// FailedAttempts returns the number of failed attempts to solve the task.
+func (s *Session) FailedAttempts(task *data.Task) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.attempts[task.Chapter().Lecture().Id][task.TID()]
+}
+
+// HintRequested counts a hint requested by the user.
+func (s *Session) HintRequested(task *data.Task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.hints = s.increment(s.hints, task)
+}
+
+// HintsRequested returns the number of hints requested by the user.
+func (s *Session) HintsRequested(task *data.Task) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.hints[task.Chapter().Lecture().Id][task.TID()]
+}
+
+// MistakesMade counts the mistakes detected in the answer of the user.
+func (s *Session) MistakesMade(task *data.Task, mistakes []string) {
+	if len(mistakes) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.mistakes == nil {
+		s.mistakes = make(map[data.LectureId]map[data.TaskId]map[string]int)
+	}
+
+	lectureId := task.Chapter().Lecture().Id
+	lmap, ok := s.mistakes[lectureId]
+	if !ok {
+		lmap = make(map[data.TaskId]map[string]int)
+		s.mistakes[lectureId] = lmap
+	}
+	tmap, ok := lmap[task.TID()]
+	if !ok {
+		tmap = make(map[string]int)
+		lmap[task.TID()] = tmap
+	}
+
+	s.dataModified = true
+	for _, m := range mistakes {
+		tmap[m]++
+	}
+}
+
+// increment increments the counter of the given task.
+// The map is created if necessary.
+func (s *Session) increment(m map[data.LectureId]map[data.TaskId]int, task *data.Task) map[data.LectureId]map[data.TaskId]int {
+	if m == nil {
+		m = make(map[data.LectureId]map[data.TaskId]int)
+	}
+
+	lectureId := task.Chapter().Lecture().Id
+	lmap, ok := m[lectureId]
+	if !ok {
+		lmap = make(map[data.TaskId]int)
+		m[lectureId] = lmap
+	}
+
+	s.dataModified = true
+	lmap[task.TID()]++
+	return m
+}
+
 // IsTaskCompleted returns true if the task is completed.
 func (s *Session) IsTaskCompleted(task *data.Task) bool {
 	s.mutex.Lock()
@@ -90,7 +238,7 @@ func (s *Session) persist(path string) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if s.completed == nil {
+	if s.completed == nil && s.scores == nil && s.attempts == nil && s.hints == nil && s.mistakes == nil && s.exams == nil && s.reviews == nil {
 		return
 	}
 
@@ -99,7 +247,7 @@ func (s *Session) persist(path string) {
 	}
 
 	var b bytes.Buffer
-	err := serialize.New().Write(&b, s.completed)
+	err := serialize.New().Write(&b, persistData{Completed: s.completed, Scores: s.scores, Attempts: s.attempts, Hints: s.hints, Mistakes: s.mistakes, Exams: s.exams, Reviews: s.reviews})
 	if err != nil {
 		log.Println("error serializing session data", err)
 		return
@@ -124,35 +272,80 @@ func (s *Session) restore(path string) {
 		log.Println("error reading session data", err)
 		return
 	}
-	s.completed = make(map[data.LectureId]map[data.TaskId]int64)
-	err = serialize.New().Read(bytes.NewReader(fileData), &s.completed)
+	var pd persistData
+	err = serialize.New().Read(bytes.NewReader(fileData), &pd)
 	if err != nil {
-		log.Println("error unmarshal session data", err)
+		// files written by older versions contain only the completed tasks
+		var completed map[data.LectureId]map[data.TaskId]int64
+		if serialize.New().Read(bytes.NewReader(fileData), &completed) == nil {
+			pd = persistData{Completed: completed}
+		} else if pd.Completed == nil {
+			log.Println("error unmarshal session data", err)
+		}
 	}
+	s.completed = pd.Completed
+	if s.completed == nil {
+		s.completed = make(map[data.LectureId]map[data.TaskId]int64)
+	}
+	s.scores = pd.Scores
+	s.attempts = pd.Attempts
+	s.hints = pd.Hints
+	s.mistakes = pd.Mistakes
+	s.exams = pd.Exams
+	s.reviews = pd.Reviews
 }
 
 // cleanup removes all completed tasks that are not in the lecture list.
 // This is necessary because the lecture list can change.
 // If not cleaned up, the session data would contain tasks that do not exist anymore.
+// Tasks whose id has changed are migrated to the new id before.
+// Exams of chapters which got an id are moved to this id.
 func (s *Session) cleanup(lectures *data.Lectures) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if s.completed == nil {
-		return
+	for _, lec := range lectures.List() {
+		if cleanupTasks(lec, s.completed[lec.LID()]) {
+			s.dataModified = true
+		}
+		if cleanupTasks(lec, s.scores[lec.LID()]) {
+			s.dataModified = true
+		}
+		if cleanupTasks(lec, s.attempts[lec.LID()]) {
+			s.dataModified = true
+		}
+		if cleanupTasks(lec, s.hints[lec.LID()]) {
+			s.dataModified = true
+		}
+		if cleanupTasks(lec, s.mistakes[lec.LID()]) {
+			s.dataModified = true
+		}
+		if cleanupTasks(lec, s.reviews[lec.LID()]) {
+			s.dataModified = true
+		}
+		if data.MigrateStateIds(lec, s.exams[lec.LID()]) {
+			s.dataModified = true
+		}
 	}
+}
 
-	for _, lec := range lectures.List() {
-		if lmap, ok := s.completed[lec.LID()]; ok {
-			for tid := range lmap {
-				if !lec.HasTask(tid) {
-					delete(lmap, tid)
-					s.dataModified = true
+// cleanupTasks removes the tasks not available in the lecture from the map.
+// If a task id has changed, the entry is moved to the new id.
+// Returns true if the map was modified.
+func cleanupTasks[V any](lec *data.Lecture, lmap map[data.TaskId]V) bool {
+	modified := false
+	for tid, v := range lmap {
+		if !lec.HasTask(tid) {
+			if newId, ok := lec.MigrateId(tid); ok {
+				if _, exists := lmap[newId]; !exists {
+					lmap[newId] = v
 				}
 			}
+			delete(lmap, tid)
+			modified = true
 		}
 	}
-
+	return modified
 }
 
 type Sessions struct {
@@ -208,19 +401,51 @@ func New(dataFolder string, lectures *data.Lectures) *Sessions {
 	return s
 }
 
+// LectureStats contains the data of a single user for a lecture
+type LectureStats struct {
+	Completed map[data.TaskId]int64
+	Scores    map[data.TaskId]float64
+	Attempts  map[data.TaskId]int
+	Hints     map[data.TaskId]int
+	Mistakes  map[data.TaskId]map[string]int
+}
+
+// Score returns the best score of the task
+func (ls LectureStats) Score(tid data.TaskId) float64 {
+	if _, ok := ls.Completed[tid]; ok {
+		return 1
+	}
+	return ls.Scores[tid]
+}
+
 // Stats returns the statistics for a lecture.
 // All stored session data is scanned for the given lecture hash.
+func (s *Sessions) Stats(lid data.LectureId) ([]LectureStats, error) {
+	var found []LectureStats
+	err := s.scan(func(token string, se *Session) {
+		c, cok := se.completed[lid]
+		sc, sok := se.scores[lid]
+		at, aok := se.attempts[lid]
+		hi, hok := se.hints[lid]
+		mi, mok := se.mistakes[lid]
+		if cok || sok || aok || hok || mok {
+			found = append(found, LectureStats{Completed: c, Scores: sc, Attempts: at, Hints: hi, Mistakes: mi})
+		}
+	})
+	return found, err
+}
+
+// scan calls the given function for all stored sessions.
 // This function also removes old session data.
 // All session files are reloaded from disc to avoid data races with
 // active sessions
-func (s *Sessions) Stats(lid data.LectureId) ([]map[data.TaskId]int64, error) {
+func (s *Sessions) scan(found func(token string, se *Session)) error {
 	s.PersistAll()
 
 	list, err := os.ReadDir(s.dataFolder)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var found []map[data.TaskId]int64
 	for _, f := range list {
 		if !f.IsDir() {
 			filePath := filepath.Join(s.dataFolder, f.Name())
@@ -236,12 +461,11 @@ func (s *Sessions) Stats(lid data.LectureId) ([]map[data.TaskId]int64, error) {
 
 			se := &Session{}
 			se.restore(filePath)
-			if c, ok := se.completed[lid]; ok {
-				found = append(found, c)
-			}
+			se.cleanup(s.lectures)
+			found(f.Name(), se)
 		}
 	}
-	return found, nil
+	return nil
 }
 
 const cookieName = "sessionId"
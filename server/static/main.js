@@ -1,3 +1,18 @@
 function goto(path) {
     window.location.href=path;
-}
\ No newline at end of file
+}
+
+function countdown(id, seconds) {
+    const end = Date.now() + seconds * 1000;
+    const elem = document.getElementById(id);
+    const update = () => {
+        const left = Math.max(0, Math.round((end - Date.now()) / 1000));
+        elem.textContent = Math.floor(left / 60) + ":" + String(left % 60).padStart(2, "0");
+        if (left > 0) {
+            setTimeout(update, 1000);
+        } else {
+            window.location.replace(window.location.href);
+        }
+    };
+    update();
+}